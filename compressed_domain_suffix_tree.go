@@ -0,0 +1,358 @@
+package domain_suffix_trie
+
+import (
+	"strings"
+)
+
+// CompressedDomainSuffixTrieNode
+//
+//	@Description: 域名后缀树的基数树（radix tree）变体，借鉴了httprouter/gin等路由树的做法：
+//	              把链上只有一个孩子的节点压缩成一条持有多个label的边，用来降低节点数量、减少内存占用并减少查询时的指针跳转
+type CompressedDomainSuffixTrieNode[T any] struct {
+
+	// EdgeLabels
+	//  @Description: 这个节点对应的这条边上压缩的label链，EdgeLabels[0]是离父节点最近的一级，
+	//                EdgeLabels[len-1]才是这个节点自己对应的那一级
+	EdgeLabels []string
+
+	// ParentNode
+	//  @Description: 此节点的父节点
+	ParentNode *CompressedDomainSuffixTrieNode[T]
+
+	// ChildrenNodeMap
+	//  @Description: 此节点的孩子节点，用每个孩子EdgeLabels的第一个label作为key，方便按label做分叉查找
+	ChildrenNodeMap map[string]*CompressedDomainSuffixTrieNode[T]
+
+	// Payload
+	//  @Description: 关联到从根路径到这个节点的这条后缀路径上的一些额外信息
+	Payload T
+
+	// hasPayload 标记这个节点是否被显式设置过payload，用来和只是压缩链中间经过的节点区分开
+	hasPayload bool
+}
+
+var _ CompressedDomainSuffixTrieInterface[any] = &CompressedDomainSuffixTrieNode[any]{}
+
+// NewCompressedDomainSuffixTrie
+//
+//	@Description: 创建一颗新的压缩域名后缀树，将这颗树的根节点返回
+//	@return *CompressedDomainSuffixTrieNode[T]:
+func NewCompressedDomainSuffixTrie[T any]() *CompressedDomainSuffixTrieNode[T] {
+	return &CompressedDomainSuffixTrieNode[T]{
+		ChildrenNodeMap: make(map[string]*CompressedDomainSuffixTrieNode[T]),
+	}
+}
+
+// newCompressedDomainSuffixTrieNode
+//
+//	@Description: 创建一个持有labels这条边、挂在parent下面的新节点，但是不会把它挂到parent的ChildrenNodeMap上
+//	@param labels:
+//	@param parent:
+//	@return *CompressedDomainSuffixTrieNode[T]:
+func newCompressedDomainSuffixTrieNode[T any](labels []string, parent *CompressedDomainSuffixTrieNode[T]) *CompressedDomainSuffixTrieNode[T] {
+	return &CompressedDomainSuffixTrieNode[T]{
+		EdgeLabels:      append([]string(nil), labels...),
+		ParentNode:      parent,
+		ChildrenNodeMap: make(map[string]*CompressedDomainSuffixTrieNode[T]),
+	}
+}
+
+// setChild
+//
+//	@Description: 把childNode以它EdgeLabels的第一个label为key挂到当前节点的孩子节点上
+//	@receiver x:
+//	@param childNode:
+func (x *CompressedDomainSuffixTrieNode[T]) setChild(childNode *CompressedDomainSuffixTrieNode[T]) {
+	x.ChildrenNodeMap[childNode.EdgeLabels[0]] = childNode
+}
+
+// GetNodeTrieValue
+//
+//	@Description: 获取当前节点自己对应的那一级label，比如 com --> google --> api，如果当前节点是在api这个节点上，则此方法返回 "api"
+//	@receiver x:
+//	@return string:
+func (x *CompressedDomainSuffixTrieNode[T]) GetNodeTrieValue() string {
+	if len(x.EdgeLabels) == 0 {
+		return ""
+	}
+	return x.EdgeLabels[len(x.EdgeLabels)-1]
+}
+
+// GetNodeTriePath
+//
+//	@Description: 获取当前节点对应的后缀路径，比如 com --> google --> api，如果当前节点是在api这个节点上，则此方法返回 "api.google.com"
+//	@receiver x:
+//	@return string:
+func (x *CompressedDomainSuffixTrieNode[T]) GetNodeTriePath() string {
+	valueSlice := make([]string, 0)
+	currentNode := x
+	for currentNode != nil && len(currentNode.EdgeLabels) != 0 {
+		for i := len(currentNode.EdgeLabels) - 1; i >= 0; i-- {
+			valueSlice = append(valueSlice, currentNode.EdgeLabels[i])
+		}
+		currentNode = currentNode.ParentNode
+	}
+	return strings.Join(valueSlice, ".")
+}
+
+// GetChildrenNodeMap
+//
+//	@Description: 返回当前节点的所有孩子节点，注意返回的是一个拷贝，树是不允许直接修改的
+//	@receiver x:
+//	@return map[string]*CompressedDomainSuffixTrieNode[T]:
+func (x *CompressedDomainSuffixTrieNode[T]) GetChildrenNodeMap() map[string]*CompressedDomainSuffixTrieNode[T] {
+	copyChildrenNodeMap := make(map[string]*CompressedDomainSuffixTrieNode[T])
+	for key, value := range x.ChildrenNodeMap {
+		copyChildrenNodeMap[key] = value
+	}
+	return copyChildrenNodeMap
+}
+
+// GetChildNode
+//
+//	@Description: 按照这一级label获取当前节点的孩子节点，label必须恰好落在一个孩子节点上，落在压缩边中间算不存在
+//	@receiver x:
+//	@param childTrieValue:
+//	@return *CompressedDomainSuffixTrieNode[T]:
+//	@return bool:
+func (x *CompressedDomainSuffixTrieNode[T]) GetChildNode(childTrieValue string) (*CompressedDomainSuffixTrieNode[T], bool) {
+	child, exists := x.ChildrenNodeMap[childTrieValue]
+	if !exists || len(child.EdgeLabels) != 1 {
+		return nil, false
+	}
+	return child, true
+}
+
+// SetPayload
+//
+//	@Description: 修改节点所绑定的payload，允许在节点创建之后修改其绑定的payload
+//	@receiver x:
+//	@param payload:
+//	@return T:
+func (x *CompressedDomainSuffixTrieNode[T]) SetPayload(payload T) T {
+	lastPayload := x.Payload
+	x.Payload = payload
+	x.hasPayload = true
+	return lastPayload
+}
+
+// GetPayload
+//
+//	@Description: 获取当前节点绑定的payload
+//	@receiver x:
+//	@return T:
+func (x *CompressedDomainSuffixTrieNode[T]) GetPayload() T {
+	return x.Payload
+}
+
+// HasPayload
+//
+//	@Description: 判断当前节点是否被显式设置过payload
+//	@receiver x:
+//	@return bool:
+func (x *CompressedDomainSuffixTrieNode[T]) HasPayload() bool {
+	return x.hasPayload
+}
+
+// commonPrefixLen 返回a和b从下标0开始逐个元素比较的公共前缀长度
+func commonPrefixLen(a, b []string) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	i := 0
+	for i < n && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// reverseDomainLabels 把domain按.分割，并且倒序排列，这样第一个元素就是最顶级的label
+func reverseDomainLabels(domain string) []string {
+	levels := strings.Split(domain, ".")
+	reversed := make([]string, len(levels))
+	for i, v := range levels {
+		reversed[len(levels)-1-i] = v
+	}
+	return reversed
+}
+
+// AddDomainSuffix
+//
+//	@Description: 添加域名后缀到压缩字典树上，插入时如果新的后缀跟已有的边只有部分公共前缀，会把那条边在公共前缀处劈开重新挂接
+//	@receiver x:
+//	@param domainSuffix: 要添加的域名后缀
+//	@param payload: 可以为这个后缀绑定一些payload，在后面拿域名匹配到这个后缀的时候可以一起获取到这个payload
+//	@return error: 如果添加后缀到树上时发生错误则返回error，否则返回nil
+func (x *CompressedDomainSuffixTrieNode[T]) AddDomainSuffix(domainSuffix string, payload T) error {
+	if domainSuffix == "" {
+		return DomainSuffixIsEmptyError
+	}
+
+	remaining := reverseDomainLabels(domainSuffix)
+	currentNode := x
+
+	for {
+		child, exists := currentNode.ChildrenNodeMap[remaining[0]]
+		if !exists {
+			leaf := newCompressedDomainSuffixTrieNode(remaining, currentNode)
+			currentNode.setChild(leaf)
+			leaf.SetPayload(payload)
+			return nil
+		}
+
+		commonLen := commonPrefixLen(remaining, child.EdgeLabels)
+
+		switch {
+		case commonLen == len(remaining) && commonLen == len(child.EdgeLabels):
+			// 新后缀和这条边完全对上了
+			child.SetPayload(payload)
+			return nil
+
+		case commonLen == len(child.EdgeLabels):
+			// 这条边被完全消耗掉了，继续往子节点走
+			remaining = remaining[commonLen:]
+			currentNode = child
+			continue
+
+		case commonLen == len(remaining):
+			// 新后缀在这条边的中间结束，要把边在commonLen处劈开，劈开点就是新后缀落脚的节点
+			splitNode := newCompressedDomainSuffixTrieNode(child.EdgeLabels[:commonLen], currentNode)
+			child.EdgeLabels = child.EdgeLabels[commonLen:]
+			child.ParentNode = splitNode
+			splitNode.setChild(child)
+			currentNode.setChild(splitNode)
+			splitNode.SetPayload(payload)
+			return nil
+
+		default:
+			// 公共前缀比新后缀和原来的边都短，劈开之后两边各自带走剩下的部分
+			splitNode := newCompressedDomainSuffixTrieNode(child.EdgeLabels[:commonLen], currentNode)
+			child.EdgeLabels = child.EdgeLabels[commonLen:]
+			child.ParentNode = splitNode
+			splitNode.setChild(child)
+			currentNode.setChild(splitNode)
+
+			leaf := newCompressedDomainSuffixTrieNode(remaining[commonLen:], splitNode)
+			splitNode.setChild(leaf)
+			leaf.SetPayload(payload)
+			return nil
+		}
+	}
+}
+
+// FindMatchDomainSuffixNode
+//
+//	@Description: 根据域名查询所匹配的后缀所对应的节点，遵循最长匹配原则，语义和DomainSuffixTrieNode.FindMatchDomainSuffixNode一致：
+//	              查询过程中途经的节点可能只是压缩链上没有被显式设置过payload的结构性节点（比如多个后缀分叉出来的split节点，
+//	              甚至是根节点自己），这些都不能算作匹配，所以从查询停下来的节点开始沿着ParentNode往上找第一个绑定了payload的节点，
+//	              一路找到根都没有的话就说明domain没有匹配到任何通过AddDomainSuffix/Compact注册过的后缀，返回nil
+//	@receiver x:
+//	@param domain: 要匹配的域名，比如 www.google.com
+//	@return *CompressedDomainSuffixTrieNode[T]: 匹配到的后缀所对应的节点，如果没有匹配到的话则返回nil
+func (x *CompressedDomainSuffixTrieNode[T]) FindMatchDomainSuffixNode(domain string) *CompressedDomainSuffixTrieNode[T] {
+	remaining := reverseDomainLabels(domain)
+	currentNode := x
+
+	for len(remaining) > 0 {
+		child, exists := currentNode.ChildrenNodeMap[remaining[0]]
+		if !exists {
+			break
+		}
+
+		commonLen := commonPrefixLen(remaining, child.EdgeLabels)
+		if commonLen < len(child.EdgeLabels) {
+			// 查询只能推进到这条边的中间，没有落在任何节点上，维持上一个完整匹配到的节点
+			break
+		}
+
+		currentNode = child
+		remaining = remaining[commonLen:]
+	}
+
+	for node := currentNode; node != nil; node = node.ParentNode {
+		if node.hasPayload {
+			return node
+		}
+	}
+	return nil
+}
+
+// FindMatchDomainSuffixPayload
+//
+//	@Description: 根据域名查询所匹配的后缀所对应的payload，遵循最长匹配原则
+//	@receiver x:
+//	@param domain: 要匹配的域名，比如 www.google.com
+//	@return T: 匹配到的后缀所绑定的payload，如果没有匹配到的话则返回T的零值
+func (x *CompressedDomainSuffixTrieNode[T]) FindMatchDomainSuffixPayload(domain string) T {
+	node := x.FindMatchDomainSuffixNode(domain)
+	if node == nil {
+		var zero T
+		return zero
+	}
+	return node.GetPayload()
+}
+
+// Compact
+//
+//	@Description: 把一颗已经填充好的DomainSuffixTrieNode转换成对应的压缩版本，返回一颗全新的CompressedDomainSuffixTrieNode树，源树本身不会被修改，
+//	              转换过程中只会合并那些没有被显式设置过payload、并且只有一个孩子的中间节点，带payload的节点永远会被保留成单独的边界节点。
+//	              CompressedDomainSuffixTrieNode目前只认字面量的label，还没有`*`/`:name`/`**`这几种通配符孩子的匹配语义，
+//	              所以如果源树上任何一个节点挂了这些通配符孩子，会返回CompressedTrieWildcardUnsupportedError而不是把这些规则静默丢掉
+//	@receiver x:
+//	@return *CompressedDomainSuffixTrieNode[T]:
+//	@return error: 源树上存在通配符孩子节点时返回CompressedTrieWildcardUnsupportedError
+func (x *DomainSuffixTrieNode[T]) Compact() (*CompressedDomainSuffixTrieNode[T], error) {
+	if x.hasWildcardChildren() {
+		return nil, CompressedTrieWildcardUnsupportedError
+	}
+
+	compressedRoot := NewCompressedDomainSuffixTrie[T]()
+	compressedRoot.Payload = x.Payload
+	compressedRoot.hasPayload = x.hasPayload
+
+	for _, child := range x.ChildrenNodeMap {
+		if err := compressedRoot.compactChild(child); err != nil {
+			return nil, err
+		}
+	}
+	return compressedRoot, nil
+}
+
+// compactChild
+//
+//	@Description: 从node开始尽可能地把只有一个孩子且没有自己payload的中间节点合并进一条边里，并把结果挂到x下面。
+//	              合并链上的任意一个节点只要挂了通配符孩子就会中断合并并返回CompressedTrieWildcardUnsupportedError，
+//	              避免这些通配符孩子既没被合并进边里、也没被当成普通孩子递归下去，被原地静默丢掉
+//	@receiver x:
+//	@param node:
+//	@return error: node及其合并链、子树上存在通配符孩子节点时返回CompressedTrieWildcardUnsupportedError
+func (x *CompressedDomainSuffixTrieNode[T]) compactChild(node *DomainSuffixTrieNode[T]) error {
+	labels := []string{node.TrieValue}
+	current := node
+
+	for !current.hasPayload && len(current.ChildrenNodeMap) == 1 && !current.hasWildcardChildren() {
+		var onlyChild *DomainSuffixTrieNode[T]
+		for _, c := range current.ChildrenNodeMap {
+			onlyChild = c
+		}
+		labels = append(labels, onlyChild.TrieValue)
+		current = onlyChild
+	}
+
+	if current.hasWildcardChildren() {
+		return CompressedTrieWildcardUnsupportedError
+	}
+
+	compressedChild := newCompressedDomainSuffixTrieNode(labels, x)
+	compressedChild.Payload = current.Payload
+	compressedChild.hasPayload = current.hasPayload
+	x.setChild(compressedChild)
+
+	for _, grandChild := range current.ChildrenNodeMap {
+		if err := compressedChild.compactChild(grandChild); err != nil {
+			return err
+		}
+	}
+	return nil
+}
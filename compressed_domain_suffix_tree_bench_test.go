@@ -0,0 +1,87 @@
+package domain_suffix_trie
+
+import (
+	"fmt"
+	"testing"
+)
+
+// buildBenchmarkSuffixes 构造一份规模和PSL差不多大的测试数据集（约1万条后缀），
+// 用固定规则生成而不是真的去下载public_suffix_list.dat，方便离线跑benchmark
+func buildBenchmarkSuffixes() []string {
+	tlds := []string{"com", "net", "org", "io", "co", "xyz", "dev"}
+	words := []string{
+		"alpha", "beta", "gamma", "delta", "epsilon", "zeta", "eta", "theta",
+		"iota", "kappa", "lambda", "mu", "nu", "xi", "omicron", "pi", "rho",
+		"sigma", "tau", "upsilon", "phi", "chi", "psi", "omega", "north", "south",
+		"east", "west", "cloud", "shop", "mail", "api", "cdn", "edge", "store",
+	}
+
+	suffixes := make([]string, 0, len(tlds)*len(words)*len(words))
+	for _, tld := range tlds {
+		for _, a := range words {
+			suffixes = append(suffixes, fmt.Sprintf("%s.%s", a, tld))
+			for _, b := range words {
+				suffixes = append(suffixes, fmt.Sprintf("%s.%s.%s", b, a, tld))
+			}
+		}
+	}
+	return suffixes
+}
+
+func BenchmarkDomainSuffixTrieNode_AddDomainSuffix(b *testing.B) {
+	suffixes := buildBenchmarkSuffixes()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		trie := NewDomainSuffixTrie[int]()
+		for _, suffix := range suffixes {
+			_ = trie.AddDomainSuffix(suffix, 1)
+		}
+	}
+}
+
+func BenchmarkCompressedDomainSuffixTrieNode_AddDomainSuffix(b *testing.B) {
+	suffixes := buildBenchmarkSuffixes()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		trie := NewCompressedDomainSuffixTrie[int]()
+		for _, suffix := range suffixes {
+			_ = trie.AddDomainSuffix(suffix, 1)
+		}
+	}
+}
+
+func BenchmarkDomainSuffixTrieNode_FindMatchDomainSuffixNode(b *testing.B) {
+	suffixes := buildBenchmarkSuffixes()
+	trie := NewDomainSuffixTrie[int]()
+	for _, suffix := range suffixes {
+		_ = trie.AddDomainSuffix(suffix, 1)
+	}
+
+	query := "www." + suffixes[len(suffixes)/2]
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		trie.FindMatchDomainSuffixNode(query)
+	}
+}
+
+func BenchmarkCompressedDomainSuffixTrieNode_FindMatchDomainSuffixNode(b *testing.B) {
+	suffixes := buildBenchmarkSuffixes()
+	trie := NewCompressedDomainSuffixTrie[int]()
+	for _, suffix := range suffixes {
+		_ = trie.AddDomainSuffix(suffix, 1)
+	}
+
+	query := "www." + suffixes[len(suffixes)/2]
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		trie.FindMatchDomainSuffixNode(query)
+	}
+}
@@ -0,0 +1,76 @@
+package domain_suffix_trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressedDomainSuffixTrieNode_AddDomainSuffix(t *testing.T) {
+	trie := NewCompressedDomainSuffixTrie[string]()
+	err := trie.AddDomainSuffix("google.com", "谷歌")
+	assert.Nil(t, err)
+	payload := trie.FindMatchDomainSuffixPayload("api.google.com")
+	assert.Equal(t, "谷歌", payload)
+}
+
+func TestCompressedDomainSuffixTrieNode_EdgeSplit(t *testing.T) {
+	trie := NewCompressedDomainSuffixTrie[string]()
+
+	// map.google.com 和 music.google.com 在google这一级之后分叉，会共享"google.com"这条边，
+	// 然后各自拆出"map"和"music"两条新边
+	assert.Nil(t, trie.AddDomainSuffix("map.google.com", "谷歌地图"))
+	assert.Nil(t, trie.AddDomainSuffix("music.google.com", "谷歌音乐"))
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌主站"))
+
+	assert.Equal(t, "谷歌地图", trie.FindMatchDomainSuffixPayload("test.map.google.com"))
+	assert.Equal(t, "谷歌音乐", trie.FindMatchDomainSuffixPayload("test.music.google.com"))
+	assert.Equal(t, "谷歌主站", trie.FindMatchDomainSuffixPayload("test.google.com"))
+}
+
+func TestCompressedDomainSuffixTrieNode_FindMatchDomainSuffixNode_NoMatchReturnsNil(t *testing.T) {
+	trie := NewCompressedDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌"))
+
+	// totally-unrelated.org跟google.com没有任何共享的后缀，连第一级"org"都没能进到任何孩子节点里，
+	// 不能把压根没匹配到任何东西的根节点当成匹配结果返回
+	assert.Nil(t, trie.FindMatchDomainSuffixNode("totally-unrelated.org"))
+	assert.Equal(t, "", trie.FindMatchDomainSuffixPayload("totally-unrelated.org"))
+
+	// a.b.com和a.c.com会在"com"这一级分叉出一个没有显式设置过payload的split节点，
+	// 查询一个同样落在"com"下但跟a无关的域名时，不能把这个结构性的split节点当成匹配结果返回
+	trie2 := NewCompressedDomainSuffixTrie[string]()
+	assert.Nil(t, trie2.AddDomainSuffix("a.b.com", "ab"))
+	assert.Nil(t, trie2.AddDomainSuffix("a.c.com", "ac"))
+	assert.Nil(t, trie2.FindMatchDomainSuffixNode("x.com"))
+}
+
+func TestCompressedDomainSuffixTrieNode_GetNodeTriePath(t *testing.T) {
+	trie := NewCompressedDomainSuffixTrie[string]()
+	err := trie.AddDomainSuffix("api.google.com", "谷歌api")
+	assert.Nil(t, err)
+
+	node := trie.FindMatchDomainSuffixNode("api.google.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "api.google.com", node.GetNodeTriePath())
+}
+
+func TestDomainSuffixTrieNode_Compact(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌主站"))
+	assert.Nil(t, trie.AddDomainSuffix("map.google.com", "谷歌地图"))
+
+	compressed, err := trie.Compact()
+	assert.Nil(t, err)
+	assert.Equal(t, "谷歌主站", compressed.FindMatchDomainSuffixPayload("test.google.com"))
+	assert.Equal(t, "谷歌地图", compressed.FindMatchDomainSuffixPayload("test.map.google.com"))
+}
+
+func TestDomainSuffixTrieNode_Compact_WildcardUnsupported(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("*.example.com", "通配符匹配"))
+
+	compressed, err := trie.Compact()
+	assert.Nil(t, compressed)
+	assert.Equal(t, CompressedTrieWildcardUnsupportedError, err)
+}
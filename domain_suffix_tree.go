@@ -27,10 +27,31 @@ type DomainSuffixTrieNode[T any] struct {
 	//  @thread-safe: 因为是可以动态的往树上添加后缀的，因此孩子节点也是会动态改变的，
 	ChildrenNodeMap map[string]*DomainSuffixTrieNode[T]
 
+	// wildChild
+	//  @Description: 用来匹配`*`这一级的孩子节点，`*`只会匹配一级label，但是不会捕获它的值
+	wildChild *DomainSuffixTrieNode[T]
+
+	// paramChild
+	//  @Description: 用来匹配`:name`这一级的孩子节点，跟wildChild一样只会匹配一级label，但是会把匹配到的值捕获到名为paramName的参数里
+	paramChild *DomainSuffixTrieNode[T]
+
+	// paramName
+	//  @Description: 当这个节点是某个节点的paramChild时，这里记录的是`:name`中的name，用来在捕获到参数之后放到结果的map里
+	paramName string
+
+	// catchAll
+	//  @Description: 用来匹配`**`这一级的孩子节点，`**`会匹配零到多级剩余的label，匹配到之后不会再继续往下匹配
+	catchAll *DomainSuffixTrieNode[T]
+
 	// Payload
 	//  @Description: 关联到从根路径到子节点的这条后缀路径上的一些额外信息，
 	//                可以给某个域名后缀指定一些附加信息，当匹配的时候就能把它取回来
 	Payload T
+
+	// hasPayload
+	//  @Description: 标记这个节点是否被AddDomainSuffix显式设置过payload，用来跟"只是路径上经过的中间节点"区分开，
+	//                比如Compact()要依赖这个字段判断一个中间节点能不能被压缩进一条边里
+	hasPayload bool
 }
 
 var _ DomainSuffixTrieInterface[any] = &DomainSuffixTrieNode[any]{}
@@ -97,6 +118,93 @@ func (x *DomainSuffixTrieNode[T]) GetChildNode(childTrieValue string) (*DomainSu
 	return childNode, exists
 }
 
+// GetWildChild
+//
+//	@Description: 获取当前节点匹配`*`这一级的孩子节点
+//	@receiver x:
+//	@return *DomainSuffixTrieNode[T]:
+//	@return bool:
+func (x *DomainSuffixTrieNode[T]) GetWildChild() (*DomainSuffixTrieNode[T], bool) {
+	return x.wildChild, x.wildChild != nil
+}
+
+// GetParamChild
+//
+//	@Description: 获取当前节点匹配`:name`这一级的孩子节点
+//	@receiver x:
+//	@return *DomainSuffixTrieNode[T]:
+//	@return bool:
+func (x *DomainSuffixTrieNode[T]) GetParamChild() (*DomainSuffixTrieNode[T], bool) {
+	return x.paramChild, x.paramChild != nil
+}
+
+// GetCatchAllChild
+//
+//	@Description: 获取当前节点匹配`**`这一级的孩子节点
+//	@receiver x:
+//	@return *DomainSuffixTrieNode[T]:
+//	@return bool:
+func (x *DomainSuffixTrieNode[T]) GetCatchAllChild() (*DomainSuffixTrieNode[T], bool) {
+	return x.catchAll, x.catchAll != nil
+}
+
+// hasWildcardChildren
+//
+//	@Description: 判断当前节点是否挂了`*`/`:name`/`**`这几种通配符孩子中的任意一个，
+//	              这几个孩子不在ChildrenNodeMap里，只遍历ChildrenNodeMap的代码（比如Compact、BuildReadOnlyTrie）
+//	              需要靠这个方法识别出自己漏看了哪些节点
+//	@receiver x:
+//	@return bool:
+func (x *DomainSuffixTrieNode[T]) hasWildcardChildren() bool {
+	return x.wildChild != nil || x.paramChild != nil || x.catchAll != nil
+}
+
+// allChildren
+//
+//	@Description: 返回当前节点全部的孩子节点，除了ChildrenNodeMap里的字面量孩子之外，
+//	              还包括wildChild、paramChild、catchAll这几个通配符孩子，
+//	              供Marshal这种需要完整遍历整棵树的场景使用，避免只看ChildrenNodeMap而漏掉通配符子树
+//	@receiver x:
+//	@return []*DomainSuffixTrieNode[T]:
+func (x *DomainSuffixTrieNode[T]) allChildren() []*DomainSuffixTrieNode[T] {
+	children := make([]*DomainSuffixTrieNode[T], 0, len(x.ChildrenNodeMap)+3)
+	for _, child := range x.ChildrenNodeMap {
+		children = append(children, child)
+	}
+	if x.wildChild != nil {
+		children = append(children, x.wildChild)
+	}
+	if x.paramChild != nil {
+		children = append(children, x.paramChild)
+	}
+	if x.catchAll != nil {
+		children = append(children, x.catchAll)
+	}
+	return children
+}
+
+// attachChild
+//
+//	@Description: 根据child.TrieValue的形式（普通label、`*`、`:name`、`**`）把child正确地挂到x对应的字段上，
+//	              跟addOrGetChild不一样的地方是child已经是一个构造好的节点（比如Unmarshal时从字节流里还原出来的），
+//	              这里只负责路由、挂载，不负责创建
+//	@receiver x:
+//	@param child:
+func (x *DomainSuffixTrieNode[T]) attachChild(child *DomainSuffixTrieNode[T]) {
+	child.ParentNode = x
+	switch {
+	case child.TrieValue == "**":
+		x.catchAll = child
+	case strings.HasPrefix(child.TrieValue, ":"):
+		child.paramName = strings.TrimPrefix(child.TrieValue, ":")
+		x.paramChild = child
+	case child.TrieValue == "*":
+		x.wildChild = child
+	default:
+		x.addChild(child)
+	}
+}
+
 // addChild
 //
 //	@Description: 为当前节点添加孩子节点
@@ -121,9 +229,19 @@ func (x *DomainSuffixTrieNode[T]) addChild(childNode *DomainSuffixTrieNode[T]) *
 func (x *DomainSuffixTrieNode[T]) SetPayload(payload T) T {
 	lastPayload := x.Payload
 	x.Payload = payload
+	x.hasPayload = true
 	return lastPayload
 }
 
+// HasPayload
+//
+//	@Description: 判断当前节点是否被显式设置过payload，用来和只是路径上经过的中间节点区分开
+//	@receiver x:
+//	@return bool:
+func (x *DomainSuffixTrieNode[T]) HasPayload() bool {
+	return x.hasPayload
+}
+
 // GetPayload
 //
 //	@Description: 获取当前节点绑定的payload
@@ -133,6 +251,122 @@ func (x *DomainSuffixTrieNode[T]) GetPayload() T {
 	return x.Payload
 }
 
+// cloneShallow
+//
+//	@Description: 浅拷贝当前节点：TrieValue、Payload等标量字段原样拷贝，ChildrenNodeMap会拷贝一份新的map，
+//	              但是map里的孩子指针还是指向原来的孩子，不会递归拷贝整棵子树。
+//	              这是SyncDomainSuffixTrieNode实现写时拷贝（copy-on-write）更新时的基础：只有插入路径上的节点需要被克隆，
+//	              没有被修改到的子树可以被新旧两棵树共享
+//	@receiver x:
+//	@return *DomainSuffixTrieNode[T]:
+func (x *DomainSuffixTrieNode[T]) cloneShallow() *DomainSuffixTrieNode[T] {
+	clone := *x
+	clone.ChildrenNodeMap = make(map[string]*DomainSuffixTrieNode[T], len(x.ChildrenNodeMap))
+	for k, v := range x.ChildrenNodeMap {
+		clone.ChildrenNodeMap[k] = v
+	}
+	return &clone
+}
+
+// cloneOrCreateChild
+//
+//	@Description: 跟addOrGetChild一样负责把v这一级对应的孩子节点路由到字面量map、paramChild、wildChild或者catchAll上，
+//	              区别是遇到已经存在的孩子时不会直接复用它，而是先cloneShallow一份再往下走，
+//	              这样调用方（比如SyncDomainSuffixTrieNode的写时拷贝更新逻辑）可以放心地在返回的节点上做修改，
+//	              不会影响到这个孩子原来所在的那棵（可能正被其他goroutine并发读取的）树
+//	@receiver x: 调用方必须保证x本身已经是一份可以安全修改的克隆
+//	@param v:
+//	@return *DomainSuffixTrieNode[T]:
+func (x *DomainSuffixTrieNode[T]) cloneOrCreateChild(v string) *DomainSuffixTrieNode[T] {
+	switch {
+	case v == "**":
+		if x.catchAll == nil {
+			x.catchAll = x.newChild(v)
+		} else {
+			x.catchAll = x.catchAll.cloneShallow()
+			x.catchAll.ParentNode = x
+		}
+		return x.catchAll
+	case strings.HasPrefix(v, ":"):
+		if x.paramChild == nil {
+			x.paramChild = x.newChild(v)
+			x.paramChild.paramName = strings.TrimPrefix(v, ":")
+		} else {
+			x.paramChild = x.paramChild.cloneShallow()
+			x.paramChild.ParentNode = x
+		}
+		return x.paramChild
+	case v == "*":
+		if x.wildChild == nil {
+			x.wildChild = x.newChild(v)
+		} else {
+			x.wildChild = x.wildChild.cloneShallow()
+			x.wildChild.ParentNode = x
+		}
+		return x.wildChild
+	default:
+		if existing, exists := x.ChildrenNodeMap[v]; exists {
+			cloned := existing.cloneShallow()
+			cloned.ParentNode = x
+			x.ChildrenNodeMap[v] = cloned
+			return cloned
+		}
+		node := x.newChild(v)
+		x.addChild(node)
+		return node
+	}
+}
+
+// newChild
+//
+//	@Description: 创建一个值为v的孩子节点，但是不会挂到父节点上
+//	@receiver x:
+//	@param v:
+//	@return *DomainSuffixTrieNode[T]:
+func (x *DomainSuffixTrieNode[T]) newChild(v string) *DomainSuffixTrieNode[T] {
+	return &DomainSuffixTrieNode[T]{
+		ChildrenNodeMap: make(map[string]*DomainSuffixTrieNode[T]),
+		TrieValue:       v,
+		ParentNode:      x,
+	}
+}
+
+// addOrGetChild
+//
+//	@Description: 把v这一级对应的孩子节点插入到当前节点上，如果已经存在则直接复用，
+//	              `*`、`:name`、`**`这几种label是路由风格的通配符，分别落到wildChild、paramChild、catchAll这几个专用字段上，
+//	              而不是和普通的字面量孩子节点混在一个map里
+//	@receiver x:
+//	@param v: 域名的某一级label
+//	@return *DomainSuffixTrieNode[T]:
+func (x *DomainSuffixTrieNode[T]) addOrGetChild(v string) *DomainSuffixTrieNode[T] {
+	switch {
+	case v == "**":
+		if x.catchAll == nil {
+			x.catchAll = x.newChild(v)
+		}
+		return x.catchAll
+	case strings.HasPrefix(v, ":"):
+		if x.paramChild == nil {
+			x.paramChild = x.newChild(v)
+			x.paramChild.paramName = strings.TrimPrefix(v, ":")
+		}
+		return x.paramChild
+	case v == "*":
+		if x.wildChild == nil {
+			x.wildChild = x.newChild(v)
+		}
+		return x.wildChild
+	default:
+		if node, exists := x.ChildrenNodeMap[v]; exists {
+			return node
+		}
+		node := x.newChild(v)
+		x.addChild(node)
+		return node
+	}
+}
+
 // AddDomainSuffix
 //
 //	@Description: 添加域名后缀追到字典树上
@@ -147,25 +381,12 @@ func (x *DomainSuffixTrieNode[T]) AddDomainSuffix(domainSuffix string, payload T
 		return DomainSuffixIsEmptyError
 	}
 
-	// 然后就是将每个级别对应上往树上放就可以了，放的时候是倒序放的
-	domainLevelValueSlice := strings.Split(domainSuffix, ".")
+	// 然后就是将每个级别对应上往树上放就可以了，用WalkLabelsReverse按从右往左的顺序遍历，不用额外分配一个[]string
 	currentNode := x
-	for index := len(domainLevelValueSlice) - 1; index >= 0; index-- {
-		v := domainLevelValueSlice[index]
-
-		// 要把v插入到currentNode的孩子节点上，先看看之前是不是已经存在过
-		if node, exists := currentNode.ChildrenNodeMap[v]; exists {
-			currentNode = node
-		} else {
-			node := &DomainSuffixTrieNode[T]{
-				ChildrenNodeMap: make(map[string]*DomainSuffixTrieNode[T]),
-				TrieValue:       v,
-				ParentNode:      currentNode,
-			}
-			currentNode.addChild(node)
-			currentNode = node
-		}
-	}
+	WalkLabelsReverse(domainSuffix, func(v string) bool {
+		currentNode = currentNode.addOrGetChild(v)
+		return true
+	})
 	// 都放完了把对应的信息放在叶子节点上
 	//if currentNode.Payload != nil {
 	//	return DomainSuffixRepetitionError
@@ -184,20 +405,81 @@ func (x *DomainSuffixTrieNode[T]) AddDomainSuffix(domainSuffix string, payload T
 //	@param domain: 要匹配的域名，比如 www.google.com
 //	@return *SyncDomainSuffixTrieNode: 匹配到的后缀所对应的TreeNode，如果没有匹配到的话则返回nil
 func (x *DomainSuffixTrieNode[T]) FindMatchDomainSuffixNode(domain string) *DomainSuffixTrieNode[T] {
-	// 对输入的域名切割为不同的级别
-	domainLevelValueSlice := strings.Split(domain, ".")
-	// 然后倒着去字典树中匹配，采用最长匹配策略
-	currentNode := x // x is root
-	for level := len(domainLevelValueSlice) - 1; level >= 0; level-- {
-		v := domainLevelValueSlice[level]
-		node, exists := currentNode.ChildrenNodeMap[v]
-		if exists {
-			currentNode = node
-		} else {
-			return currentNode
+	node, _ := x.FindMatchDomainSuffixWithParams(domain)
+	return node
+}
+
+// FindMatchDomainSuffixWithParams
+//
+//	@Description: 跟FindMatchDomainSuffixNode一样遵循最长匹配原则查询节点，不过在匹配路径上经过`:name`这种参数节点时，
+//	              会把实际匹配到的label值捕获下来一并返回，在每一级上字面量孩子节点优先于`:name`，`:name`优先于`*`，`*`优先于`**`，
+//	              `**`可以匹配零到多级剩余的label。如果优先级更高的分支最终没能走到一个绑定了payload的节点，
+//	              会回溯回来尝试这一级优先级更低的分支，这样才不会被一条半路走不通的字面量分支挡住本该命中的`:name`/`*`/`**`分支
+//	@receiver x:
+//	@param domain: 要匹配的域名，比如 www.google.com
+//	@return *DomainSuffixTrieNode[T]: 匹配到的后缀所对应的TreeNode，如果没有匹配到的话则返回nil
+//	@return map[string]string: 匹配路径上捕获到的参数，如果没有经过任何`:name`节点则返回nil
+func (x *DomainSuffixTrieNode[T]) FindMatchDomainSuffixWithParams(domain string) (*DomainSuffixTrieNode[T], map[string]string) {
+	// 先用WalkLabelsReverse倒着把各级label收集起来，因为回溯需要能重新尝试更靠前的分支，不能像单趟遍历那样即走即丢
+	labels := make([]string, 0, 4)
+	WalkLabelsReverse(domain, func(v string) bool {
+		labels = append(labels, v)
+		return true
+	})
+
+	return x.matchLabels(labels, 0, nil)
+}
+
+// matchLabels
+//
+//	@Description: FindMatchDomainSuffixWithParams的递归实现，从当前节点出发尝试用labels[idx:]继续往下匹配。
+//	              每一级按字面量、`:name`、`*`的优先级依次尝试，一旦选中的分支最终没能走到一个绑定了payload的节点就回溯回来尝试下一个分支；
+//	              如果所有分支都走不通（或者根本没有更多label可以继续往下走），当前节点自己绑定的payload就是兜底的匹配——
+//	              这对应的是"剩下的label是某个已注册后缀之下任意的子域名"这种最长匹配场景，再往后是`**`兜底，最后才是彻底没匹配上
+//	@receiver x:
+//	@param labels: domain按.分割后的各级label，顺序是从右往左（比如www.google.com对应[com, google, www]）
+//	@param idx: 下一级要匹配的label在labels里的下标，等于len(labels)表示labels已经消费完了
+//	@param params: 从根到x这条路径上已经捕获到的参数
+//	@return *DomainSuffixTrieNode[T]: 匹配到的节点，如果这个分支走不通则返回nil
+//	@return map[string]string:
+func (x *DomainSuffixTrieNode[T]) matchLabels(labels []string, idx int, params map[string]string) (*DomainSuffixTrieNode[T], map[string]string) {
+	if idx < len(labels) {
+		v := labels[idx]
+
+		if child, exists := x.ChildrenNodeMap[v]; exists {
+			if node, matchedParams := child.matchLabels(labels, idx+1, params); node != nil {
+				return node, matchedParams
+			}
 		}
+
+		if x.paramChild != nil {
+			childParams := make(map[string]string, len(params)+1)
+			for k, p := range params {
+				childParams[k] = p
+			}
+			childParams[x.paramChild.paramName] = v
+			if node, matchedParams := x.paramChild.matchLabels(labels, idx+1, childParams); node != nil {
+				return node, matchedParams
+			}
+		}
+
+		if x.wildChild != nil {
+			if node, matchedParams := x.wildChild.matchLabels(labels, idx+1, params); node != nil {
+				return node, matchedParams
+			}
+		}
+	}
+
+	if x.hasPayload {
+		return x, params
 	}
-	return currentNode
+
+	// **匹配零到多级剩余的label，不管labels是否已经消费完都要在这里兜底检查一次
+	if x.catchAll != nil && x.catchAll.hasPayload {
+		return x.catchAll, params
+	}
+
+	return nil, nil
 }
 
 // FindMatchDomainSuffixPayload
@@ -0,0 +1,76 @@
+package domain_suffix_trie
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixNode_Wild(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix("*.example.com", "通配符匹配")
+	assert.Nil(t, err)
+
+	node := tire.FindMatchDomainSuffixNode("api.example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "通配符匹配", node.GetPayload())
+}
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixWithParams(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix(":tenant.example.com", "租户子域名")
+	assert.Nil(t, err)
+
+	node, params := tire.FindMatchDomainSuffixWithParams("acme.example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "租户子域名", node.GetPayload())
+	assert.Equal(t, "acme", params["tenant"])
+}
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixNode_CatchAll(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix("**.example.com", "兜底匹配")
+	assert.Nil(t, err)
+
+	node := tire.FindMatchDomainSuffixNode("a.b.c.example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "兜底匹配", node.GetPayload())
+}
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixNode_LiteralPreferredOverWild(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix("*.example.com", "通配符匹配")
+	assert.Nil(t, err)
+	err = tire.AddDomainSuffix("www.example.com", "字面量匹配")
+	assert.Nil(t, err)
+
+	node := tire.FindMatchDomainSuffixNode("www.example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "字面量匹配", node.GetPayload())
+}
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixNode_CatchAll_ZeroExtraLabels(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix("**.example.com", "兜底匹配")
+	assert.Nil(t, err)
+
+	// **要能匹配零到多级剩余的label，example.com本身没有多出来的label，也应该命中兜底规则
+	node := tire.FindMatchDomainSuffixNode("example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "兜底匹配", node.GetPayload())
+}
+
+func TestDomainSuffixTrieNode_FindMatchDomainSuffixWithParams_BacktrackPastDeadEndLiteral(t *testing.T) {
+	tire := NewDomainSuffixTrie[string]()
+	err := tire.AddDomainSuffix(":id.example.com", "租户子域名")
+	assert.Nil(t, err)
+	err = tire.AddDomainSuffix("www.foo.example.com", "foo站点的www")
+	assert.Nil(t, err)
+
+	// foo这一级字面量上挂着www.foo.example.com这条无关的规则，但是它自己没有payload，
+	// 不应该挡住:id这个参数分支本该命中的匹配
+	node, params := tire.FindMatchDomainSuffixWithParams("foo.example.com")
+	assert.NotNil(t, node)
+	assert.Equal(t, "租户子域名", node.GetPayload())
+	assert.Equal(t, "foo", params["id"])
+}
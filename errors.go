@@ -4,3 +4,11 @@ import "errors"
 
 // DomainSuffixIsEmptyError 错误：域名后缀是空的
 var DomainSuffixIsEmptyError = errors.New("域名后缀是空的")
+
+// DomainIsPublicSuffixError 错误：域名本身就是一个公共后缀，没有办法再取它的eTLD+1
+var DomainIsPublicSuffixError = errors.New("域名本身就是一个公共后缀")
+
+// CompressedTrieWildcardUnsupportedError 错误：压缩字典树（CompressedDomainSuffixTrieNode）和只读字典树（ReadOnlyTrie）
+// 都还没有`*`/`:name`/`**`这几种通配符孩子的匹配语义，如果源字典树上挂了这些通配符节点，Compact/BuildReadOnlyTrie会
+// 返回这个error而不是静默地把这些规则丢掉
+var CompressedTrieWildcardUnsupportedError = errors.New("压缩字典树/只读字典树暂不支持通配符孩子节点，无法压缩或者序列化")
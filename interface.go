@@ -21,6 +21,15 @@ type DomainSuffixTrieInterface[T any] interface {
 	//	@return *SyncDomainSuffixTrieNode: 匹配到的后缀所对应的TreeNode，如果没有匹配到的话则返回nil
 	FindMatchDomainSuffixNode(domain string) *DomainSuffixTrieNode[T]
 
+	// FindMatchDomainSuffixWithParams
+	//
+	//	@Description: 跟FindMatchDomainSuffixNode一样遵循最长匹配原则查询节点，不过会把匹配路径上`:name`这种参数节点捕获到的值一并返回
+	//	@receiver x:
+	//	@param domain: 要匹配的域名，比如 www.google.com
+	//	@return *DomainSuffixTrieNode[T]: 匹配到的后缀所对应的TreeNode，如果没有匹配到的话则返回nil
+	//	@return map[string]string: 匹配路径上捕获到的参数，如果没有经过任何`:name`节点则返回nil
+	FindMatchDomainSuffixWithParams(domain string) (*DomainSuffixTrieNode[T], map[string]string)
+
 	// AddDomainSuffix
 	//
 	//	@Description: 添加域名后缀追到字典树上，如果已经存在的话则会更新之前的值
@@ -75,3 +84,78 @@ type DomainSuffixTrieInterface[T any] interface {
 	//	@return string:
 	GetNodeTrieValue() string
 }
+
+// CompressedDomainSuffixTrieInterface 压缩版域名后缀树的接口定义，跟DomainSuffixTrieInterface形状一致，
+// 只是节点类型换成了把单孩子链压缩成一条边的CompressedDomainSuffixTrieNode
+type CompressedDomainSuffixTrieInterface[T any] interface {
+
+	// FindMatchDomainSuffixPayload
+	//
+	//	@Description: 根据域名查询所匹配的后缀所对应的payload，语义跟DomainSuffixTrieInterface.FindMatchDomainSuffixPayload一致
+	//	@receiver x:
+	//	@param domain: 要匹配的域名，比如 www.google.com
+	//	@return T:
+	FindMatchDomainSuffixPayload(domain string) T
+
+	// FindMatchDomainSuffixNode
+	//
+	//	@Description: 根据域名查询所匹配的后缀所对应的节点，语义跟DomainSuffixTrieInterface.FindMatchDomainSuffixNode一致
+	//	@receiver x:
+	//	@param domain: 要匹配的域名，比如 www.google.com
+	//	@return *CompressedDomainSuffixTrieNode[T]:
+	FindMatchDomainSuffixNode(domain string) *CompressedDomainSuffixTrieNode[T]
+
+	// AddDomainSuffix
+	//
+	//	@Description: 添加域名后缀追到压缩字典树上，如果已经存在的话则会更新之前的值
+	//	@receiver x:
+	//	@param domainSuffix:
+	//	@param payload:
+	//	@return error:
+	AddDomainSuffix(domainSuffix string, payload T) error
+
+	// GetPayload
+	//
+	//	@Description: 获取当前节点绑定的payload
+	//	@receiver x:
+	//	@return T:
+	GetPayload() T
+
+	// SetPayload
+	//
+	//	@Description: 修改节点所绑定的payload
+	//	@receiver x:
+	//	@param payload:
+	//	@return T:
+	SetPayload(payload T) T
+
+	// GetChildNode
+	//
+	//	@Description: 按照label获取当前节点的孩子节点，label必须恰好落在一个孩子节点上
+	//	@receiver x:
+	//	@param childTrieValue:
+	//	@return *CompressedDomainSuffixTrieNode[T]:
+	//	@return bool:
+	GetChildNode(childTrieValue string) (*CompressedDomainSuffixTrieNode[T], bool)
+
+	// GetChildrenNodeMap
+	//
+	//	@Description: 返回当前节点的所有孩子节点，注意返回的是一个拷贝
+	//	@receiver x:
+	//	@return map[string]*CompressedDomainSuffixTrieNode[T]:
+	GetChildrenNodeMap() map[string]*CompressedDomainSuffixTrieNode[T]
+
+	// GetNodeTriePath
+	//
+	//	@Description: 获取当前节点对应的字典后缀路径
+	//	@receiver x:
+	//	@return string:
+	GetNodeTriePath() string
+
+	// GetNodeTrieValue
+	//
+	//	@Description: 获取当前节点对应的字典值
+	//	@receiver x:
+	//	@return string:
+	GetNodeTrieValue() string
+}
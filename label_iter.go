@@ -0,0 +1,25 @@
+package domain_suffix_trie
+
+import "strings"
+
+// WalkLabelsReverse
+//
+//	@Description: 从右往左按.切分domain，依次把每一级label传给fn，fn返回false的话会提前终止遍历。
+//	              跟strings.Split(domain, ".")不一样的是，这里不会为了保存各级label分配一个[]string，
+//	              每一级label都是domain本身的切片，没有额外的内存分配，适合AddDomainSuffix/FindMatchDomainSuffixNode
+//	              这种需要频繁调用的热路径
+//	@param domain: 要遍历的域名，比如 www.google.com
+//	@param fn: 依次收到每一级label（www.google.com会按com、google、www的顺序收到），返回false可以提前结束遍历
+func WalkLabelsReverse(domain string, fn func(label string) bool) {
+	end := len(domain)
+	for end >= 0 {
+		idx := strings.LastIndexByte(domain[:end], '.')
+		if !fn(domain[idx+1 : end]) {
+			return
+		}
+		if idx < 0 {
+			return
+		}
+		end = idx
+	}
+}
@@ -0,0 +1,46 @@
+package domain_suffix_trie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkLabelsReverse(t *testing.T) {
+	var labels []string
+	WalkLabelsReverse("www.api.google.com", func(label string) bool {
+		labels = append(labels, label)
+		return true
+	})
+	assert.Equal(t, []string{"com", "google", "api", "www"}, labels)
+}
+
+func TestWalkLabelsReverse_EarlyStop(t *testing.T) {
+	var labels []string
+	WalkLabelsReverse("www.api.google.com", func(label string) bool {
+		labels = append(labels, label)
+		return label != "google"
+	})
+	assert.Equal(t, []string{"com", "google"}, labels)
+}
+
+func BenchmarkWalkLabelsReverse(b *testing.B) {
+	domain := "www.api.google.com"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		WalkLabelsReverse(domain, func(label string) bool {
+			return true
+		})
+	}
+}
+
+func BenchmarkStringsSplit(b *testing.B) {
+	domain := "www.api.google.com"
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = strings.Split(domain, ".")
+	}
+}
@@ -0,0 +1,52 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LoadAdblock
+//
+//	@Description: 流式解析adblock风格的规则列表（形如EasyList），只识别`||example.com^`这种域名屏蔽规则，
+//	              `!`开头的注释行和`##`/`#@#`这种cosmetic filter规则会被忽略
+//	@param r: adblock规则文件内容
+//	@param payloadFn: 根据域名生成这条记录要绑定的payload
+//	@return <-chan Entry[T]: 解析出来的记录流，读完之后会被关闭，可以直接交给BulkAdd使用
+func LoadAdblock[T any](r io.Reader, payloadFn func(domain string) T) <-chan Entry[T] {
+	out := make(chan Entry[T])
+
+	go func() {
+		defer close(out)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "!") || strings.HasPrefix(line, "[") {
+				continue
+			}
+
+			// cosmetic filter不是域名屏蔽规则，跳过
+			if strings.Contains(line, "##") || strings.Contains(line, "#@#") {
+				continue
+			}
+
+			if !strings.HasPrefix(line, "||") {
+				continue
+			}
+			domain := strings.TrimPrefix(line, "||")
+
+			// 域名后面可能还跟着^、/path、*这类修饰符，只取域名本身
+			if idx := strings.IndexAny(domain, "^/*"); idx != -1 {
+				domain = domain[:idx]
+			}
+			if domain == "" {
+				continue
+			}
+
+			out <- Entry[T]{Domain: domain, Payload: payloadFn(domain)}
+		}
+	}()
+
+	return out
+}
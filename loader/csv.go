@@ -0,0 +1,42 @@
+package loader
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// LoadCSV
+//
+//	@Description: 流式解析CSV格式的域名列表，每一行交给parseRow决定这一行对应的域名和payload是什么，
+//	              parseRow返回ok=false时这一行会被跳过（比如表头行、格式不对的行）
+//	@param r: CSV文件内容
+//	@param parseRow: 把一行record解析成domain和payload，ok=false表示跳过这一行
+//	@return <-chan Entry[T]: 解析出来的记录流，读完之后会被关闭，可以直接交给BulkAdd使用
+func LoadCSV[T any](r io.Reader, parseRow func(record []string) (domain string, payload T, ok bool)) <-chan Entry[T] {
+	out := make(chan Entry[T])
+
+	go func() {
+		defer close(out)
+
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				return
+			}
+
+			domain, payload, ok := parseRow(record)
+			if !ok {
+				continue
+			}
+			out <- Entry[T]{Domain: domain, Payload: payload}
+		}
+	}()
+
+	return out
+}
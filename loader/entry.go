@@ -0,0 +1,115 @@
+package loader
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// Entry
+//
+//	@Description: 表示一条要导入到字典树里的记录，LoadHosts/LoadAdblock/LoadPSL/LoadCSV都是把各自的输入格式解析成Entry的流，
+//	              再统一交给BulkAdd写入字典树
+type Entry[T any] struct {
+
+	// Domain 要添加的域名后缀
+	Domain string
+
+	// Payload 这个后缀绑定的payload
+	Payload T
+}
+
+// Target
+//
+//	@Description: BulkAdd所需要的最小接口，DomainSuffixTrieNode、SyncDomainSuffixTrieNode、CompressedDomainSuffixTrieNode都满足这个接口
+type Target[T any] interface {
+	AddDomainSuffix(domainSuffix string, payload T) error
+}
+
+// BulkAdd
+//
+//	@Description: 把entries这个流里的记录逐条写入target，写入之前会校验、小写化、Punycode编码每一级label并且去重，
+//	              方便直接把EasyList、StevenBlack hosts、PSL这类现成的黑名单灌进字典树，而不用各自手写一遍解析代码
+//	@param target: 接收写入的字典树
+//	@param entries: LoadHosts/LoadAdblock/LoadPSL/LoadCSV产生的记录流
+//	@return added: 成功写入的条数
+//	@return skipped: 因为校验失败或者重复而跳过的条数
+//	@return errs: 写入过程中发生的错误，每条跳过的记录至多对应一个error
+func BulkAdd[T any](target Target[T], entries <-chan Entry[T]) (added int, skipped int, errs []error) {
+	seen := make(map[string]struct{})
+
+	for entry := range entries {
+		domain, err := NormalizeDomain(entry.Domain)
+		if err != nil {
+			skipped++
+			errs = append(errs, fmt.Errorf("%s: %w", entry.Domain, err))
+			continue
+		}
+
+		if _, exists := seen[domain]; exists {
+			skipped++
+			continue
+		}
+		seen[domain] = struct{}{}
+
+		if err := target.AddDomainSuffix(domain, entry.Payload); err != nil {
+			skipped++
+			errs = append(errs, fmt.Errorf("%s: %w", domain, err))
+			continue
+		}
+		added++
+	}
+
+	return added, skipped, errs
+}
+
+// NormalizeDomain
+//
+//	@Description: 校验并且规范化一个域名后缀：去掉结尾的.、整体转小写、把每一级非ASCII的label转成Punycode，
+//	              同时校验每一级label长度不超过63字节、整个域名长度不超过253字节
+//	              `*`、`**`、`:name`这几种字典树自己的通配符label会被原样保留，不会被当成非法字符拒绝
+//	@param domain:
+//	@return string: 规范化之后的域名后缀
+//	@return error: domain不合法时返回错误
+func NormalizeDomain(domain string) (string, error) {
+	domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(domain)), ".")
+	if domain == "" {
+		return "", fmt.Errorf("domain is empty")
+	}
+
+	labels := strings.Split(domain, ".")
+	normalized := make([]string, len(labels))
+	totalLen := 0
+
+	for i, label := range labels {
+		if label == "" {
+			return "", fmt.Errorf("domain %q has an empty label", domain)
+		}
+
+		if isWildcardLabel(label) {
+			normalized[i] = label
+		} else {
+			ascii, err := idna.ToASCII(label)
+			if err != nil {
+				return "", fmt.Errorf("label %q is not a valid domain label: %w", label, err)
+			}
+			if len(ascii) > 63 {
+				return "", fmt.Errorf("label %q exceeds 63 bytes", ascii)
+			}
+			normalized[i] = ascii
+		}
+		totalLen += len(normalized[i]) + 1
+	}
+
+	if totalLen-1 > 253 {
+		return "", fmt.Errorf("domain %q exceeds 253 bytes", domain)
+	}
+
+	return strings.Join(normalized, "."), nil
+}
+
+// isWildcardLabel 判断一级label是不是字典树自己的通配符标记（`*`、`**`、`:name`），这几种不是真正的DNS label，不需要做IDNA校验
+func isWildcardLabel(label string) bool {
+	return label == "*" || label == "**" || strings.HasPrefix(label, ":")
+}
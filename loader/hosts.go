@@ -0,0 +1,46 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LoadHosts
+//
+//	@Description: 流式解析hosts文件格式（形如 StevenBlack hosts列表）："IP 域名 [别名...]"，一行可能绑定多个域名，
+//	              `#`之后的内容当作注释忽略
+//	@param r: hosts文件内容
+//	@param payloadFn: 根据域名生成这条记录要绑定的payload
+//	@return <-chan Entry[T]: 解析出来的记录流，读完之后会被关闭，可以直接交给BulkAdd使用
+func LoadHosts[T any](r io.Reader, payloadFn func(domain string) T) <-chan Entry[T] {
+	out := make(chan Entry[T])
+
+	go func() {
+		defer close(out)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if idx := strings.IndexByte(line, '#'); idx != -1 {
+				line = line[:idx]
+			}
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+
+			fields := strings.Fields(line)
+			if len(fields) < 2 {
+				continue
+			}
+
+			// 第一列是IP，后面几列都是指向这个IP的域名/别名
+			for _, domain := range fields[1:] {
+				out <- Entry[T]{Domain: domain, Payload: payloadFn(domain)}
+			}
+		}
+	}()
+
+	return out
+}
@@ -0,0 +1,120 @@
+package loader
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTarget 是一个满足Target[string]接口的最简单实现，单测里用来断言BulkAdd真的把记录写进去了
+type fakeTarget struct {
+	payloadByDomain map[string]string
+}
+
+func newFakeTarget() *fakeTarget {
+	return &fakeTarget{payloadByDomain: make(map[string]string)}
+}
+
+func (f *fakeTarget) AddDomainSuffix(domainSuffix string, payload string) error {
+	f.payloadByDomain[domainSuffix] = payload
+	return nil
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	domain, err := NormalizeDomain("WWW.Example.COM.")
+	assert.Nil(t, err)
+	assert.Equal(t, "www.example.com", domain)
+
+	_, err = NormalizeDomain("")
+	assert.NotNil(t, err)
+
+	_, err = NormalizeDomain("a..com")
+	assert.NotNil(t, err)
+
+	domain, err = NormalizeDomain("*.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "*.example.com", domain)
+}
+
+func TestLoadHosts(t *testing.T) {
+	data := "127.0.0.1 localhost\n0.0.0.0 ads.example.com tracker.example.com # 注释\n\n"
+	entries := LoadHosts(strings.NewReader(data), func(domain string) string { return "blocked" })
+
+	target := newFakeTarget()
+	added, skipped, errs := BulkAdd[string](target, entries)
+
+	assert.Equal(t, 3, added)
+	assert.Equal(t, 0, skipped)
+	assert.Empty(t, errs)
+	assert.Equal(t, "blocked", target.payloadByDomain["ads.example.com"])
+	assert.Equal(t, "blocked", target.payloadByDomain["tracker.example.com"])
+}
+
+func TestLoadAdblock(t *testing.T) {
+	data := "! 注释\n||ads.example.com^\nexample.com##.banner\n||tracker.example.com^$third-party\n"
+	entries := LoadAdblock(strings.NewReader(data), func(domain string) string { return "blocked" })
+
+	target := newFakeTarget()
+	added, _, _ := BulkAdd[string](target, entries)
+
+	assert.Equal(t, 2, added)
+	assert.Equal(t, "blocked", target.payloadByDomain["ads.example.com"])
+	assert.Equal(t, "blocked", target.payloadByDomain["tracker.example.com"])
+}
+
+func TestLoadPSL(t *testing.T) {
+	data := "// comment\ncom\n*.ck\n!www.ck\n"
+	entries := LoadPSL(strings.NewReader(data), func(domain string) string { return "psl" })
+
+	target := newFakeTarget()
+	added, _, _ := BulkAdd[string](target, entries)
+
+	assert.Equal(t, 3, added)
+	assert.Contains(t, target.payloadByDomain, "com")
+	assert.Contains(t, target.payloadByDomain, "*.ck")
+	assert.Contains(t, target.payloadByDomain, "www.ck")
+}
+
+func TestLoadCSV(t *testing.T) {
+	data := "domain,tag\nexample.com,known\nbad.example.com,blocked\n"
+	entries := LoadCSV(strings.NewReader(data), func(record []string) (string, string, bool) {
+		if len(record) != 2 || record[0] == "domain" {
+			return "", "", false
+		}
+		return record[0], record[1], true
+	})
+
+	target := newFakeTarget()
+	added, _, _ := BulkAdd[string](target, entries)
+
+	assert.Equal(t, 2, added)
+	assert.Equal(t, "known", target.payloadByDomain["example.com"])
+	assert.Equal(t, "blocked", target.payloadByDomain["bad.example.com"])
+}
+
+func TestBulkAdd_Dedup(t *testing.T) {
+	out := make(chan Entry[string], 2)
+	out <- Entry[string]{Domain: "example.com", Payload: "a"}
+	out <- Entry[string]{Domain: "EXAMPLE.com", Payload: "b"}
+	close(out)
+
+	target := newFakeTarget()
+	added, skipped, _ := BulkAdd[string](target, out)
+
+	assert.Equal(t, 1, added)
+	assert.Equal(t, 1, skipped)
+}
+
+func TestCollectSortedByDepth(t *testing.T) {
+	out := make(chan Entry[string], 3)
+	out <- Entry[string]{Domain: "api.test.example.com"}
+	out <- Entry[string]{Domain: "com"}
+	out <- Entry[string]{Domain: "example.com"}
+	close(out)
+
+	sorted := CollectSortedByDepth[string](out)
+	assert.Equal(t, "com", sorted[0].Domain)
+	assert.Equal(t, "example.com", sorted[1].Domain)
+	assert.Equal(t, "api.test.example.com", sorted[2].Domain)
+}
@@ -0,0 +1,36 @@
+package loader
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// LoadPSL
+//
+//	@Description: 流式解析Public Suffix List格式的文件，跳过注释行，例外规则(`!www.ck`)里的`!`前缀会被去掉，
+//	              只把规则本身的域名部分交给payloadFn，如果需要区分规则类型（普通/通配符/例外）和ICANN/PRIVATE分区，
+//	              应该直接用根包里的PublicSuffixList，这里提供的是跟LoadHosts/LoadAdblock一致的流式入口
+//	@param r: PSL文件内容
+//	@param payloadFn: 根据域名生成这条记录要绑定的payload
+//	@return <-chan Entry[T]: 解析出来的记录流，读完之后会被关闭，可以直接交给BulkAdd使用
+func LoadPSL[T any](r io.Reader, payloadFn func(domain string) T) <-chan Entry[T] {
+	out := make(chan Entry[T])
+
+	go func() {
+		defer close(out)
+
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "//") {
+				continue
+			}
+
+			domain := strings.TrimPrefix(line, "!")
+			out <- Entry[T]{Domain: domain, Payload: payloadFn(domain)}
+		}
+	}()
+
+	return out
+}
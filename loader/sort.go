@@ -0,0 +1,25 @@
+package loader
+
+import (
+	"sort"
+	"strings"
+)
+
+// CollectSortedByDepth
+//
+//	@Description: 把entries这个流读到内存里，并按后缀的级数从浅到深排序（比如com排在google.com前面），
+//	              这样批量插入字典树时，相邻插入的后缀大概率落在同一条路径附近，对CPU缓存更友好
+//	@param entries: LoadHosts/LoadAdblock/LoadPSL/LoadCSV产生的记录流
+//	@return []Entry[T]: 按级数升序排好的记录，可以用于直接遍历调用AddDomainSuffix，也可以重新喂给BulkAdd
+func CollectSortedByDepth[T any](entries <-chan Entry[T]) []Entry[T] {
+	collected := make([]Entry[T], 0)
+	for entry := range entries {
+		collected = append(collected, entry)
+	}
+
+	sort.SliceStable(collected, func(i, j int) bool {
+		return strings.Count(collected[i].Domain, ".") < strings.Count(collected[j].Domain, ".")
+	})
+
+	return collected
+}
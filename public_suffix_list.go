@@ -0,0 +1,180 @@
+package domain_suffix_trie
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// PublicSuffixRuleKind 标识一条PSL规则的类型
+type PublicSuffixRuleKind int
+
+const (
+
+	// PublicSuffixRuleNormal 普通规则，比如 com、github.io
+	PublicSuffixRuleNormal PublicSuffixRuleKind = iota
+
+	// PublicSuffixRuleWildcard 通配符规则，比如 *.ck，表示ck下面的任意一级都是公共后缀
+	PublicSuffixRuleWildcard
+
+	// PublicSuffixRuleException 例外规则，比如 !www.ck，表示将通配符规则中的这个特例摘出来，它本身不是公共后缀
+	PublicSuffixRuleException
+)
+
+// PublicSuffixRule 挂在字典树节点上的payload，记录这条规则的类型以及它所属的PSL分区
+type PublicSuffixRule struct {
+
+	// Kind 这条规则的类型：普通规则、通配符规则或者例外规则
+	Kind PublicSuffixRuleKind
+
+	// ICANN 为true表示这条规则来自PSL的ICANN分区，为false表示来自PRIVATE分区
+	ICANN bool
+}
+
+// PublicSuffixList
+//
+//	@Description: 基于字典树实现的Public Suffix List（PSL），可以用来判断一个域名的公共后缀(eTLD)，
+//	              以及在公共后缀之上再加一级得到的可注册域名(eTLD+1)，是标准库`golang.org/x/net/publicsuffix`的可替代实现
+type PublicSuffixList struct {
+	trie *DomainSuffixTrieNode[*PublicSuffixRule]
+}
+
+// NewPublicSuffixList
+//
+//	@Description: 创建一个空的PublicSuffixList，创建完之后需要调用LoadPublicSuffixList加载规则才能使用
+//	@return *PublicSuffixList:
+func NewPublicSuffixList() *PublicSuffixList {
+	return &PublicSuffixList{
+		trie: NewDomainSuffixTrie[*PublicSuffixRule](),
+	}
+}
+
+// LoadPublicSuffixList
+//
+//	@Description: 从Reader中读取PSL文件（即https://publicsuffix.org/list/public_suffix_list.dat的内容）并将其中的规则加载到字典树上，
+//	              可以被调用多次以合并多份规则文件
+//	@receiver x:
+//	@param r: PSL文件内容
+//	@return error: 读取规则时发生的错误
+func (x *PublicSuffixList) LoadPublicSuffixList(r io.Reader) error {
+
+	// PSL文件用注释行划分ICANN与PRIVATE两个分区，分区信息需要被记录到每条规则上
+	icann := false
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		// 注释行，同时也用来标记分区的起止
+		if strings.HasPrefix(line, "//") {
+			if strings.Contains(line, "BEGIN ICANN DOMAINS") {
+				icann = true
+			} else if strings.Contains(line, "END ICANN DOMAINS") {
+				icann = false
+			}
+			continue
+		}
+
+		rule := strings.ToLower(line)
+		kind := PublicSuffixRuleNormal
+		if strings.HasPrefix(rule, "!") {
+			kind = PublicSuffixRuleException
+			rule = rule[1:]
+		} else if strings.HasPrefix(rule, "*.") {
+			kind = PublicSuffixRuleWildcard
+		}
+
+		if err := x.trie.AddDomainSuffix(rule, &PublicSuffixRule{Kind: kind, ICANN: icann}); err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
+
+// findPublicSuffixNode
+//
+//	@Description: 在字典树上为domain寻找匹配到的PSL规则节点，以及该节点在domain中对应的级数（从右往左数）
+//	@receiver x:
+//	@param labels: domain按.分割后的各级label
+//	@return *DomainSuffixTrieNode[*PublicSuffixRule]: 匹配到的规则节点，如果一个规则都没匹配到则返回nil
+//	@return int: 匹配到的规则节点对应的级数
+func (x *PublicSuffixList) findPublicSuffixNode(labels []string) (*DomainSuffixTrieNode[*PublicSuffixRule], int) {
+	var matchedNode *DomainSuffixTrieNode[*PublicSuffixRule]
+	matchedDepth := 0
+
+	currentNode := x.trie
+	for level := len(labels) - 1; level >= 0; level-- {
+		v := labels[level]
+
+		// 优先走字面量匹配，匹配不到的话PSL允许这一级是通配符
+		child, exists := currentNode.GetChildNode(v)
+		if !exists {
+			child, exists = currentNode.GetWildChild()
+		}
+		if !exists {
+			break
+		}
+
+		currentNode = child
+		if currentNode.GetPayload() != nil {
+			matchedNode = currentNode
+			matchedDepth = len(labels) - level
+		}
+	}
+
+	return matchedNode, matchedDepth
+}
+
+// PublicSuffix
+//
+//	@Description: 查询一个域名的公共后缀(eTLD)，遵循https://publicsuffix.org/list/的匹配算法：
+//	              字面量规则优先于通配符规则，同时通配符规则允许被更长的例外规则摘掉最左边的一级
+//	@receiver x:
+//	@param domain: 要查询的域名，比如 www.example.ck
+//	@return suffix: 匹配到的公共后缀，比如 example.ck，如果一条规则都没匹配到，则退化为使用最后一级label作为公共后缀
+//	@return icann: 命中的规则是否来自PSL的ICANN分区
+func (x *PublicSuffixList) PublicSuffix(domain string) (suffix string, icann bool) {
+	domain = strings.ToLower(domain)
+	labels := strings.Split(domain, ".")
+
+	matchedNode, matchedDepth := x.findPublicSuffixNode(labels)
+	if matchedNode == nil {
+		// 没有命中任何规则时，隐含的规则是"*"，即最后一级label本身就是公共后缀
+		return labels[len(labels)-1], false
+	}
+
+	rule := matchedNode.GetPayload()
+	suffixDepth := matchedDepth
+	if rule.Kind == PublicSuffixRuleException {
+		// 例外规则要把最左边的一级摘掉，比如!www.ck命中之后公共后缀是ck而不是www.ck
+		suffixDepth--
+	}
+
+	return strings.Join(labels[len(labels)-suffixDepth:], "."), rule.ICANN
+}
+
+// EffectiveTLDPlusOne
+//
+//	@Description: 在公共后缀的基础上再往左取一级，得到域名的可注册部分，比如 www.example.com --> example.com
+//	@receiver x:
+//	@param domain: 要查询的域名
+//	@return string: 可注册域名
+//	@return error: 当domain本身就是一个公共后缀（没有再往左的一级）时返回DomainIsPublicSuffixError
+func (x *PublicSuffixList) EffectiveTLDPlusOne(domain string) (string, error) {
+	lowerDomain := strings.ToLower(domain)
+	suffix, _ := x.PublicSuffix(lowerDomain)
+
+	if len(lowerDomain) <= len(suffix) {
+		return "", DomainIsPublicSuffixError
+	}
+
+	// suffix之前的部分再按.分割一次，取最后一级拼回suffix就是eTLD+1
+	rest := lowerDomain[:len(lowerDomain)-len(suffix)-1]
+	lastDot := strings.LastIndex(rest, ".")
+
+	return rest[lastDot+1:] + "." + suffix, nil
+}
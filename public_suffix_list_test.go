@@ -0,0 +1,52 @@
+package domain_suffix_trie
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPublicSuffixListData = `
+// ===BEGIN ICANN DOMAINS===
+com
+github.io
+*.ck
+!www.ck
+// ===END ICANN DOMAINS===
+`
+
+func TestPublicSuffixList_PublicSuffix(t *testing.T) {
+	list := NewPublicSuffixList()
+	err := list.LoadPublicSuffixList(strings.NewReader(testPublicSuffixListData))
+	assert.Nil(t, err)
+
+	suffix, icann := list.PublicSuffix("www.google.com")
+	assert.Equal(t, "com", suffix)
+	assert.True(t, icann)
+
+	suffix, icann = list.PublicSuffix("foo.ck")
+	assert.Equal(t, "foo.ck", suffix)
+	assert.True(t, icann)
+
+	suffix, icann = list.PublicSuffix("www.ck")
+	assert.Equal(t, "ck", suffix)
+	assert.True(t, icann)
+}
+
+func TestPublicSuffixList_EffectiveTLDPlusOne(t *testing.T) {
+	list := NewPublicSuffixList()
+	err := list.LoadPublicSuffixList(strings.NewReader(testPublicSuffixListData))
+	assert.Nil(t, err)
+
+	domain, err := list.EffectiveTLDPlusOne("test.github.io")
+	assert.Nil(t, err)
+	assert.Equal(t, "test.github.io", domain)
+
+	domain, err = list.EffectiveTLDPlusOne("www.example.com")
+	assert.Nil(t, err)
+	assert.Equal(t, "example.com", domain)
+
+	_, err = list.EffectiveTLDPlusOne("com")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,307 @@
+package domain_suffix_trie
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	readOnlyTrieHeaderLen     = 16
+	readOnlyTrieNodeRecordLen = 24
+)
+
+// readOnlyTrieMagic 标记一份数据是BuildReadOnlyTrie写出来的只读字典树格式
+var readOnlyTrieMagic = [4]byte{'D', 'S', 'X', '1'}
+
+// BuildReadOnlyTrie
+//
+//	@Description: 把x转换成ReadOnlyTrie可以直接mmap读取的紧凑二进制格式写入w：节点按层序排布成一个数组，
+//	              每个节点的孩子在数组里是一段连续区间并且按label排好序，查询的时候可以在这段区间里做二分查找，
+//	              而不需要per-node的map，也不需要把数据解析成真正的Node对象。
+//	              这个格式和ReadOnlyTrie的二分查找都还没有`*`/`:name`/`**`这几种通配符孩子的匹配语义，
+//	              所以如果root上任何一个节点挂了这些通配符孩子，会返回CompressedTrieWildcardUnsupportedError而不是把这些规则静默丢掉
+//	@param root: 要转换的字典树的根节点
+//	@param codec: payload的编解码器
+//	@param w:
+//	@return error: root上存在通配符孩子节点时返回CompressedTrieWildcardUnsupportedError
+func BuildReadOnlyTrie[T any](root *DomainSuffixTrieNode[T], codec PayloadCodec[T], w io.Writer) error {
+	type nodeRecord struct {
+		labelOffset, labelLen     uint32
+		childStart, childCount    uint32
+		payloadOffset, payloadLen uint32
+	}
+
+	// 用BFS把树铺平成数组：每处理到一个节点就把它的孩子（已经按label排好序）整块追加到队列末尾，
+	// 这样每个节点的孩子在最终数组里天然是连续的一段
+	queue := []*DomainSuffixTrieNode[T]{root}
+	records := make([]nodeRecord, 1)
+	labelTable := make([]byte, 0)
+	payloadBlob := make([]byte, 0)
+
+	for i := 0; i < len(queue); i++ {
+		node := queue[i]
+
+		if node.hasWildcardChildren() {
+			return fmt.Errorf("%w: %s", CompressedTrieWildcardUnsupportedError, node.GetNodeTriePath())
+		}
+
+		labelBytes := []byte(node.TrieValue)
+		records[i].labelOffset = uint32(len(labelTable))
+		records[i].labelLen = uint32(len(labelBytes))
+		labelTable = append(labelTable, labelBytes...)
+
+		if node.hasPayload {
+			payloadBytes, err := codec.Encode(node.Payload)
+			if err != nil {
+				return fmt.Errorf("编码节点%q的payload失败: %w", node.GetNodeTriePath(), err)
+			}
+			records[i].payloadOffset = uint32(len(payloadBlob))
+			records[i].payloadLen = uint32(len(payloadBytes))
+			payloadBlob = append(payloadBlob, payloadBytes...)
+		}
+
+		children := make([]*DomainSuffixTrieNode[T], 0, len(node.ChildrenNodeMap))
+		for _, child := range node.ChildrenNodeMap {
+			children = append(children, child)
+		}
+		sort.Slice(children, func(a, b int) bool {
+			return children[a].TrieValue < children[b].TrieValue
+		})
+
+		records[i].childStart = uint32(len(queue))
+		records[i].childCount = uint32(len(children))
+		for _, child := range children {
+			queue = append(queue, child)
+			records = append(records, nodeRecord{})
+		}
+	}
+
+	header := make([]byte, readOnlyTrieHeaderLen)
+	copy(header[0:4], readOnlyTrieMagic[:])
+	binary.LittleEndian.PutUint32(header[4:8], uint32(len(records)))
+	binary.LittleEndian.PutUint32(header[8:12], uint32(len(labelTable)))
+	binary.LittleEndian.PutUint32(header[12:16], uint32(len(payloadBlob)))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+
+	nodeBuf := make([]byte, readOnlyTrieNodeRecordLen)
+	for _, rec := range records {
+		binary.LittleEndian.PutUint32(nodeBuf[0:4], rec.labelOffset)
+		binary.LittleEndian.PutUint32(nodeBuf[4:8], rec.labelLen)
+		binary.LittleEndian.PutUint32(nodeBuf[8:12], rec.childStart)
+		binary.LittleEndian.PutUint32(nodeBuf[12:16], rec.childCount)
+		binary.LittleEndian.PutUint32(nodeBuf[16:20], rec.payloadOffset)
+		binary.LittleEndian.PutUint32(nodeBuf[20:24], rec.payloadLen)
+		if _, err := w.Write(nodeBuf); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(labelTable); err != nil {
+		return err
+	}
+	if _, err := w.Write(payloadBlob); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadOnlyTrie
+//
+//	@Description: 只读的域名后缀树，数据来自BuildReadOnlyTrie写出来的格式，可以直接mmap一个文件来构造，
+//	              查询的时候直接在原始字节上做二分查找，不需要建立per-node的map，也不需要为每个节点分配对象
+type ReadOnlyTrie[T any] struct {
+	data   []byte
+	codec  PayloadCodec[T]
+	handle *mmapHandle
+
+	nodeCount      uint32
+	nodeTableOff   uint32
+	labelTableOff  uint32
+	payloadBlobOff uint32
+}
+
+// NewReadOnlyTrieFromBytes
+//
+//	@Description: 从已经读到内存里的字节构造ReadOnlyTrie，适合数据已经通过其他方式加载（比如测试、或者调用方自己管理mmap）的场景
+//	@param data: BuildReadOnlyTrie写出来的数据
+//	@param codec:
+//	@return *ReadOnlyTrie[T]:
+//	@return error:
+func NewReadOnlyTrieFromBytes[T any](data []byte, codec PayloadCodec[T]) (*ReadOnlyTrie[T], error) {
+	if len(data) < readOnlyTrieHeaderLen {
+		return nil, ErrInvalidSerializedTrie
+	}
+
+	var magic [4]byte
+	copy(magic[:], data[0:4])
+	if magic != readOnlyTrieMagic {
+		return nil, ErrInvalidSerializedTrie
+	}
+
+	nodeCount := binary.LittleEndian.Uint32(data[4:8])
+	labelTableLen := binary.LittleEndian.Uint32(data[8:12])
+	payloadBlobLen := binary.LittleEndian.Uint32(data[12:16])
+
+	nodeTableOff := uint32(readOnlyTrieHeaderLen)
+	labelTableOff := nodeTableOff + nodeCount*readOnlyTrieNodeRecordLen
+	payloadBlobOff := labelTableOff + labelTableLen
+
+	if uint64(payloadBlobOff)+uint64(payloadBlobLen) != uint64(len(data)) {
+		return nil, ErrInvalidSerializedTrie
+	}
+
+	return &ReadOnlyTrie[T]{
+		data:           data,
+		codec:          codec,
+		nodeCount:      nodeCount,
+		nodeTableOff:   nodeTableOff,
+		labelTableOff:  labelTableOff,
+		payloadBlobOff: payloadBlobOff,
+	}, nil
+}
+
+// OpenReadOnlyTrie
+//
+//	@Description: 把path对应的文件mmap到内存里并构造一个ReadOnlyTrie，用完之后要调用Close()把mmap释放掉
+//	@param path: BuildReadOnlyTrie写出来的文件路径
+//	@param codec:
+//	@return *ReadOnlyTrie[T]:
+//	@return error:
+func OpenReadOnlyTrie[T any](path string, codec PayloadCodec[T]) (*ReadOnlyTrie[T], error) {
+	handle, err := mmapOpen(path)
+	if err != nil {
+		return nil, err
+	}
+
+	trie, err := NewReadOnlyTrieFromBytes(handle.data, codec)
+	if err != nil {
+		_ = handle.Close()
+		return nil, err
+	}
+	trie.handle = handle
+
+	return trie, nil
+}
+
+// Close
+//
+//	@Description: 释放OpenReadOnlyTrie打开的mmap，如果是用NewReadOnlyTrieFromBytes构造的则是个空操作
+//	@receiver x:
+//	@return error:
+func (x *ReadOnlyTrie[T]) Close() error {
+	if x.handle == nil {
+		return nil
+	}
+	return x.handle.Close()
+}
+
+func (x *ReadOnlyTrie[T]) nodeRecordOffset(index uint32) uint32 {
+	return x.nodeTableOff + index*readOnlyTrieNodeRecordLen
+}
+
+func (x *ReadOnlyTrie[T]) childRange(index uint32) (start, count uint32) {
+	off := x.nodeRecordOffset(index)
+	start = binary.LittleEndian.Uint32(x.data[off+8 : off+12])
+	count = binary.LittleEndian.Uint32(x.data[off+12 : off+16])
+	return
+}
+
+func (x *ReadOnlyTrie[T]) label(index uint32) []byte {
+	off := x.nodeRecordOffset(index)
+	labelOffset := binary.LittleEndian.Uint32(x.data[off : off+4])
+	labelLen := binary.LittleEndian.Uint32(x.data[off+4 : off+8])
+	start := x.labelTableOff + labelOffset
+	return x.data[start : start+labelLen]
+}
+
+func (x *ReadOnlyTrie[T]) payloadBytes(index uint32) []byte {
+	off := x.nodeRecordOffset(index)
+	payloadOffset := binary.LittleEndian.Uint32(x.data[off+16 : off+20])
+	payloadLen := binary.LittleEndian.Uint32(x.data[off+20 : off+24])
+	if payloadLen == 0 {
+		return nil
+	}
+	start := x.payloadBlobOff + payloadOffset
+	return x.data[start : start+payloadLen]
+}
+
+// compareBytesToString 按字典序比较b和s，不需要把两者转换成同一种类型，用来在查询路径上避免分配
+func compareBytesToString(b []byte, s string) int {
+	n := len(b)
+	if len(s) < n {
+		n = len(s)
+	}
+	for i := 0; i < n; i++ {
+		if b[i] != s[i] {
+			if b[i] < s[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(b) - len(s)
+}
+
+// findChild 在nodeIndex这个节点连续排布的孩子区间里二分查找label
+func (x *ReadOnlyTrie[T]) findChild(nodeIndex uint32, label string) (uint32, bool) {
+	start, count := x.childRange(nodeIndex)
+	lo, hi := start, start+count
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		switch compareBytesToString(x.label(mid), label) {
+		case 0:
+			return mid, true
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return 0, false
+}
+
+// findMatchIndex 遵循最长匹配原则，返回domain匹配路径上最深的一个绑定了payload的节点下标，路径上一个都没有的话返回根节点下标0
+func (x *ReadOnlyTrie[T]) findMatchIndex(domain string) uint32 {
+	levels := strings.Split(domain, ".")
+	currentIndex := uint32(0) // 根节点永远是下标0
+	matchedIndex := uint32(0)
+	for level := len(levels) - 1; level >= 0; level-- {
+		childIndex, exists := x.findChild(currentIndex, levels[level])
+		if !exists {
+			break
+		}
+		currentIndex = childIndex
+		if x.payloadBytes(currentIndex) != nil {
+			matchedIndex = currentIndex
+		}
+	}
+	return matchedIndex
+}
+
+// FindMatchDomainSuffixPayload
+//
+//	@Description: 根据域名查询所匹配的后缀所对应的payload，语义跟DomainSuffixTrieNode.FindMatchDomainSuffixPayload一致
+//	@receiver x:
+//	@param domain: 要匹配的域名，比如 www.google.com
+//	@return T: 匹配到的payload
+//	@return bool: 匹配到的节点上是否真的绑定了payload
+func (x *ReadOnlyTrie[T]) FindMatchDomainSuffixPayload(domain string) (T, bool) {
+	index := x.findMatchIndex(domain)
+	payloadBytes := x.payloadBytes(index)
+	if payloadBytes == nil {
+		var zero T
+		return zero, false
+	}
+
+	value, err := x.codec.Decode(payloadBytes)
+	if err != nil {
+		var zero T
+		return zero, false
+	}
+	return value, true
+}
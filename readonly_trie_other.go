@@ -0,0 +1,26 @@
+//go:build !unix
+
+package domain_suffix_trie
+
+import "os"
+
+// mmapHandle 在非unix平台上没有mmap，退化成把文件整个读到内存里，对外的行为保持一致
+type mmapHandle struct {
+	data []byte
+}
+
+// mmapOpen 把path对应的文件整个读到内存里
+func mmapOpen(path string) (*mmapHandle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, ErrInvalidSerializedTrie
+	}
+	return &mmapHandle{data: data}, nil
+}
+
+func (h *mmapHandle) Close() error {
+	return nil
+}
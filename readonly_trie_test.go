@@ -0,0 +1,48 @@
+package domain_suffix_trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildReadOnlyTrie_FindMatchDomainSuffixPayload(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌主站"))
+	assert.Nil(t, trie.AddDomainSuffix("map.google.com", "谷歌地图"))
+	assert.Nil(t, trie.AddDomainSuffix("baidu.com", "百度主站"))
+
+	var buf bytes.Buffer
+	err := BuildReadOnlyTrie[string](trie, stringPayloadCodec{}, &buf)
+	assert.Nil(t, err)
+
+	readOnly, err := NewReadOnlyTrieFromBytes[string](buf.Bytes(), stringPayloadCodec{})
+	assert.Nil(t, err)
+
+	payload, ok := readOnly.FindMatchDomainSuffixPayload("test.google.com")
+	assert.True(t, ok)
+	assert.Equal(t, "谷歌主站", payload)
+
+	payload, ok = readOnly.FindMatchDomainSuffixPayload("test.map.google.com")
+	assert.True(t, ok)
+	assert.Equal(t, "谷歌地图", payload)
+
+	_, ok = readOnly.FindMatchDomainSuffixPayload("test.jd.com")
+	assert.False(t, ok)
+}
+
+func TestNewReadOnlyTrieFromBytes_InvalidMagic(t *testing.T) {
+	_, err := NewReadOnlyTrieFromBytes[string]([]byte("not a readonly trie"), stringPayloadCodec{})
+	assert.NotNil(t, err)
+}
+
+func TestBuildReadOnlyTrie_WildcardUnsupported(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("*.example.com", "通配符匹配"))
+
+	var buf bytes.Buffer
+	err := BuildReadOnlyTrie[string](trie, stringPayloadCodec{}, &buf)
+	assert.NotNil(t, err)
+	assert.ErrorIs(t, err, CompressedTrieWildcardUnsupportedError)
+}
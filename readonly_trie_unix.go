@@ -0,0 +1,48 @@
+//go:build unix
+
+package domain_suffix_trie
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapHandle 持有一段mmap映射以及背后的文件，Close的时候要把两者都释放掉
+type mmapHandle struct {
+	file *os.File
+	data []byte
+}
+
+// mmapOpen 把path对应的文件只读mmap到内存里
+func mmapOpen(path string) (*mmapHandle, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if fi.Size() == 0 {
+		_ = f.Close()
+		return nil, ErrInvalidSerializedTrie
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(fi.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	return &mmapHandle{file: f, data: data}, nil
+}
+
+func (h *mmapHandle) Close() error {
+	err := syscall.Munmap(h.data)
+	if closeErr := h.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
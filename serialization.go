@@ -0,0 +1,196 @@
+package domain_suffix_trie
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// serializationMagic 写在文件开头用来识别格式以及做版本校验
+var serializationMagic = [4]byte{'D', 'S', 'T', '1'}
+
+// ErrInvalidSerializedTrie 错误：读到的数据不是一个合法的序列化字典树
+var ErrInvalidSerializedTrie = errors.New("不是一个合法的序列化字典树")
+
+// PayloadCodec
+//
+//	@Description: 序列化/反序列化payload的编解码器，因为Payload是泛型的T，字典树自己没办法知道怎么把它变成字节，
+//	              所以交给调用方实现这个接口
+type PayloadCodec[T any] interface {
+
+	// Encode 把一个payload编码成字节
+	Encode(value T) ([]byte, error)
+
+	// Decode 把字节解码回payload
+	Decode(data []byte) (T, error)
+}
+
+// serializedNode 列出了Marshal为每个节点写出的字段；Marshal本身直接遍历DomainSuffixTrieNode，并不使用这个类型
+type serializedNode[T any] struct {
+	trieValue  string
+	hasPayload bool
+	payload    T
+	children   []*DomainSuffixTrieNode[T]
+}
+
+// Marshal
+//
+//	@Description: 把字典树序列化成紧凑的二进制格式写入w，每个节点的label以varint长度加字节的形式直接写在节点里
+//	              （没有单独的字符串表，重复的label不会去重），payload用codec编码，可以配合Unmarshal或者BuildReadOnlyTrie使用
+//	@receiver x:
+//	@param w:
+//	@param codec:
+//	@return error:
+func (x *DomainSuffixTrieNode[T]) Marshal(w io.Writer, codec PayloadCodec[T]) error {
+	bw := bufio.NewWriter(w)
+
+	if _, err := bw.Write(serializationMagic[:]); err != nil {
+		return err
+	}
+
+	var writeErr error
+	writeVarUint := func(v uint64) {
+		if writeErr != nil {
+			return
+		}
+		var buf [binary.MaxVarintLen64]byte
+		n := binary.PutUvarint(buf[:], v)
+		_, writeErr = bw.Write(buf[:n])
+	}
+	writeBytes := func(b []byte) {
+		writeVarUint(uint64(len(b)))
+		if writeErr != nil {
+			return
+		}
+		_, writeErr = bw.Write(b)
+	}
+
+	// 先序遍历整棵树，每个节点依次写 label、payload(有没有+内容)、孩子数量，孩子按label排序写，保证同一棵树每次写出来的字节都一样
+	var visit func(node *DomainSuffixTrieNode[T]) error
+	visit = func(node *DomainSuffixTrieNode[T]) error {
+		writeBytes([]byte(node.TrieValue))
+		if writeErr != nil {
+			return writeErr
+		}
+
+		if node.hasPayload {
+			payloadBytes, err := codec.Encode(node.Payload)
+			if err != nil {
+				return fmt.Errorf("编码节点%q的payload失败: %w", node.GetNodeTriePath(), err)
+			}
+			writeVarUint(1)
+			writeBytes(payloadBytes)
+		} else {
+			writeVarUint(0)
+		}
+		if writeErr != nil {
+			return writeErr
+		}
+
+		// allChildren除了ChildrenNodeMap里的字面量孩子之外还会带上wildChild/paramChild/catchAll，
+		// 否则`*`/`:name`/`**`这几种规则写出去的文件里就会完全没有它们的踪迹
+		children := node.allChildren()
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].TrieValue < children[j].TrieValue
+		})
+
+		writeVarUint(uint64(len(children)))
+		for _, child := range children {
+			if err := visit(child); err != nil {
+				return err
+			}
+		}
+		return writeErr
+	}
+
+	if err := visit(x); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// Unmarshal
+//
+//	@Description: 从r中读取Marshal写出来的格式，重建出一棵DomainSuffixTrieNode
+//	@param r:
+//	@param codec:
+//	@return *DomainSuffixTrieNode[T]:
+//	@return error:
+func Unmarshal[T any](r io.Reader, codec PayloadCodec[T]) (*DomainSuffixTrieNode[T], error) {
+	br := bufio.NewReader(r)
+
+	var magic [4]byte
+	if _, err := io.ReadFull(br, magic[:]); err != nil {
+		return nil, fmt.Errorf("读取magic失败: %w", err)
+	}
+	if magic != serializationMagic {
+		return nil, ErrInvalidSerializedTrie
+	}
+
+	readVarUint := func() (uint64, error) {
+		return binary.ReadUvarint(br)
+	}
+	readBytes := func() ([]byte, error) {
+		n, err := readVarUint()
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, n)
+		if _, err := io.ReadFull(br, buf); err != nil {
+			return nil, err
+		}
+		return buf, nil
+	}
+
+	var readNode func(parent *DomainSuffixTrieNode[T]) (*DomainSuffixTrieNode[T], error)
+	readNode = func(parent *DomainSuffixTrieNode[T]) (*DomainSuffixTrieNode[T], error) {
+		labelBytes, err := readBytes()
+		if err != nil {
+			return nil, err
+		}
+
+		node := &DomainSuffixTrieNode[T]{
+			TrieValue:       string(labelBytes),
+			ParentNode:      parent,
+			ChildrenNodeMap: make(map[string]*DomainSuffixTrieNode[T]),
+		}
+
+		hasPayload, err := readVarUint()
+		if err != nil {
+			return nil, err
+		}
+		if hasPayload != 0 {
+			payloadBytes, err := readBytes()
+			if err != nil {
+				return nil, err
+			}
+			payload, err := codec.Decode(payloadBytes)
+			if err != nil {
+				return nil, fmt.Errorf("解码节点的payload失败: %w", err)
+			}
+			node.SetPayload(payload)
+		}
+
+		childCount, err := readVarUint()
+		if err != nil {
+			return nil, err
+		}
+		for i := uint64(0); i < childCount; i++ {
+			child, err := readNode(node)
+			if err != nil {
+				return nil, err
+			}
+			// attachChild会根据child.TrieValue把`*`/`:name`/`**`这几种通配符孩子路由回wildChild/paramChild/catchAll，
+			// 而不是把它们当成普通的字面量孩子全部塞进ChildrenNodeMap
+			node.attachChild(child)
+		}
+
+		return node, nil
+	}
+
+	return readNode(nil)
+}
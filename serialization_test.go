@@ -0,0 +1,61 @@
+package domain_suffix_trie
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stringPayloadCodec 是最简单的PayloadCodec[string]实现，直接把字符串当成字节来编解码，单测和示例里都够用
+type stringPayloadCodec struct{}
+
+func (stringPayloadCodec) Encode(value string) ([]byte, error) {
+	return []byte(value), nil
+}
+
+func (stringPayloadCodec) Decode(data []byte) (string, error) {
+	return string(data), nil
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌主站"))
+	assert.Nil(t, trie.AddDomainSuffix("map.google.com", "谷歌地图"))
+
+	var buf bytes.Buffer
+	err := trie.Marshal(&buf, stringPayloadCodec{})
+	assert.Nil(t, err)
+
+	restored, err := Unmarshal[string](&buf, stringPayloadCodec{})
+	assert.Nil(t, err)
+	assert.Equal(t, "谷歌主站", restored.FindMatchDomainSuffixPayload("test.google.com"))
+	assert.Equal(t, "谷歌地图", restored.FindMatchDomainSuffixPayload("test.map.google.com"))
+}
+
+func TestUnmarshal_InvalidMagic(t *testing.T) {
+	_, err := Unmarshal[string](bytes.NewReader([]byte("not a trie")), stringPayloadCodec{})
+	assert.NotNil(t, err)
+}
+
+func TestMarshalUnmarshal_WildcardChildrenSurviveRoundTrip(t *testing.T) {
+	trie := NewDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("*.example.com", "通配符匹配"))
+	assert.Nil(t, trie.AddDomainSuffix(":tenant.example.org", "租户子域名"))
+	assert.Nil(t, trie.AddDomainSuffix("**.example.net", "兜底匹配"))
+
+	var buf bytes.Buffer
+	assert.Nil(t, trie.Marshal(&buf, stringPayloadCodec{}))
+
+	restored, err := Unmarshal[string](&buf, stringPayloadCodec{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "通配符匹配", restored.FindMatchDomainSuffixPayload("api.example.com"))
+
+	node, params := restored.FindMatchDomainSuffixWithParams("acme.example.org")
+	assert.NotNil(t, node)
+	assert.Equal(t, "租户子域名", node.GetPayload())
+	assert.Equal(t, "acme", params["tenant"])
+
+	assert.Equal(t, "兜底匹配", restored.FindMatchDomainSuffixPayload("a.b.example.net"))
+}
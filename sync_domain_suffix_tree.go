@@ -1,79 +1,161 @@
 package domain_suffix_trie
 
 import (
-	"strings"
 	"sync"
+	"sync/atomic"
 )
 
-// SyncDomainSuffixTrieNode 线程安全的实现
+// SyncDomainSuffixTrieNode
+//
+//	@Description: 线程安全的实现。读路径完全不用加锁：内部用atomic.Pointer指向一份不可变的树快照，
+//	              AddDomainSuffix/SetPayload走写时拷贝（copy-on-write）——只克隆插入路径上经过的节点，
+//	              没被修改到的子树新旧快照共享同一份——构建出一棵新树之后再用atomic.Pointer.Store把根指针替换过去，
+//	              替换用的是Store而不是CompareAndSwap，多个写者之间用writeLock互斥，避免两个写者基于同一份旧快照各自算出一份新快照、后Store的把先Store的覆盖掉。
+//	              这比旧版本的sync.RWMutex更适合"黑名单大部分时间只读、偶尔整批刷新，但是有海量并发查询"这种场景，
+//	              消除了读写之间的锁竞争
 type SyncDomainSuffixTrieNode[T any] struct {
-	lock sync.RWMutex
-	node *DomainSuffixTrieNode[T]
+	root      atomic.Pointer[DomainSuffixTrieNode[T]]
+	writeLock sync.Mutex
 }
 
 var _ DomainSuffixTrieInterface[any] = &SyncDomainSuffixTrieNode[any]{}
 
+// NewSyncDomainSuffixTrie
+//
+//	@Description: 创建一颗新的线程安全的域名后缀树
+//	@return *SyncDomainSuffixTrieNode[T]:
 func NewSyncDomainSuffixTrie[T any]() *SyncDomainSuffixTrieNode[T] {
-	return &SyncDomainSuffixTrieNode[T]{
-		lock: sync.RWMutex{},
-		node: NewDomainSuffixTrie[T](),
-	}
+	x := &SyncDomainSuffixTrieNode[T]{}
+	x.root.Store(NewDomainSuffixTrie[T]())
+	return x
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) FindMatchDomainSuffixPayload(domain string) T {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.FindMatchDomainSuffixPayload(domain)
+	return x.root.Load().FindMatchDomainSuffixPayload(domain)
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) FindMatchDomainSuffixNode(domain string) *DomainSuffixTrieNode[T] {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.FindMatchDomainSuffixNode(domain)
+	return x.root.Load().FindMatchDomainSuffixNode(domain)
+}
+
+func (x *SyncDomainSuffixTrieNode[T]) FindMatchDomainSuffixWithParams(domain string) (*DomainSuffixTrieNode[T], map[string]string) {
+	return x.root.Load().FindMatchDomainSuffixWithParams(domain)
 }
 
+// AddDomainSuffix
+//
+//	@Description: 添加域名后缀，内部会克隆一份从根到插入点的路径，在克隆出来的树上完成插入之后再用一次原子Store整体发布出去，
+//	              在这个方法返回之前，其他goroutine通过FindMatchDomainSuffixNode等方法读到的永远是完整的旧快照或者完整的新快照，不会读到半成品
+//	@receiver x:
+//	@param domainSuffix:
+//	@param payload:
+//	@return error:
 func (x *SyncDomainSuffixTrieNode[T]) AddDomainSuffix(domainSuffix string, payload T) error {
-	x.lock.Lock()
-	defer x.lock.Unlock()
-	return x.node.AddDomainSuffix(domainSuffix, payload)
+	if domainSuffix == "" {
+		return DomainSuffixIsEmptyError
+	}
+
+	x.writeLock.Lock()
+	defer x.writeLock.Unlock()
+
+	newRoot := x.root.Load().cloneShallow()
+
+	currentNode := newRoot
+	WalkLabelsReverse(domainSuffix, func(v string) bool {
+		currentNode = currentNode.cloneOrCreateChild(v)
+		return true
+	})
+	currentNode.SetPayload(payload)
+
+	x.root.Store(newRoot)
+	return nil
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) GetPayload() T {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.GetPayload()
+	return x.root.Load().GetPayload()
 }
 
+// SetPayload
+//
+//	@Description: 修改根节点绑定的payload，跟AddDomainSuffix一样走写时拷贝
+//	@receiver x:
+//	@param payload:
+//	@return T:
 func (x *SyncDomainSuffixTrieNode[T]) SetPayload(payload T) T {
-	x.lock.Lock()
-	defer x.lock.Unlock()
-	return x.node.SetPayload(payload)
+	x.writeLock.Lock()
+	defer x.writeLock.Unlock()
+
+	newRoot := x.root.Load().cloneShallow()
+	lastPayload := newRoot.SetPayload(payload)
+	x.root.Store(newRoot)
+	return lastPayload
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) GetChildNode(childTrieValue string) (*DomainSuffixTrieNode[T], bool) {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.GetChildNode(childTrieValue)
+	return x.root.Load().GetChildNode(childTrieValue)
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) GetChildrenNodeMap() map[string]*DomainSuffixTrieNode[T] {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.ChildrenNodeMap
+	return x.root.Load().GetChildrenNodeMap()
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) GetNodeTriePath() string {
-	valueSlice := make([]string, 0)
-	currentNode := x.node
-	for currentNode != nil && currentNode.TrieValue != "" {
-		valueSlice = append(valueSlice, currentNode.GetNodeTrieValue())
-		currentNode = currentNode.ParentNode
-	}
-	return strings.Join(valueSlice, ".")
+	return x.root.Load().GetNodeTriePath()
 }
 
 func (x *SyncDomainSuffixTrieNode[T]) GetNodeTrieValue() string {
-	x.lock.RLock()
-	defer x.lock.RUnlock()
-	return x.node.TrieValue
+	return x.root.Load().GetNodeTrieValue()
+}
+
+// Builder
+//
+//	@Description: BatchUpdate回调里唯一可以用来修改字典树的入口，一次BatchUpdate期间所有通过Builder做的插入，
+//	              都累积在同一棵新树上，只有回调成功返回之后这棵树才会被一次原子Store整体发布出去。
+//	              每一次插入仍然会把自己路径上已经存在的节点重新克隆一遍，本次批量里已经克隆过的节点也一样会被再克隆
+type Builder[T any] struct {
+	root *DomainSuffixTrieNode[T]
+}
+
+// AddDomainSuffix
+//
+//	@Description: 在本次BatchUpdate累积的拷贝上插入一条域名后缀，语义跟SyncDomainSuffixTrieNode.AddDomainSuffix一致
+//	@receiver b:
+//	@param domainSuffix:
+//	@param payload:
+//	@return error:
+func (b *Builder[T]) AddDomainSuffix(domainSuffix string, payload T) error {
+	if domainSuffix == "" {
+		return DomainSuffixIsEmptyError
+	}
+
+	currentNode := b.root
+	WalkLabelsReverse(domainSuffix, func(v string) bool {
+		currentNode = currentNode.cloneOrCreateChild(v)
+		return true
+	})
+	currentNode.SetPayload(payload)
+
+	return nil
+}
+
+// BatchUpdate
+//
+//	@Description: 在一次锁内对字典树做多次插入，根节点只克隆一次，所有插入共享同一次加锁和同一次Store发布，
+//	              比连续调用多次AddDomainSuffix少了每一条都要做的加锁、根节点克隆和发布；每一条插入仍然会克隆它自己路径上的节点。
+//	              适合批量刷新黑名单这种"一次性灌进去几千条"的场景
+//	@receiver x:
+//	@param fn: fn里通过传入的Builder调用AddDomainSuffix来插入，fn返回error的话这次更新整体作废，root不会被替换
+//	@return error:
+func (x *SyncDomainSuffixTrieNode[T]) BatchUpdate(fn func(*Builder[T]) error) error {
+	x.writeLock.Lock()
+	defer x.writeLock.Unlock()
+
+	builder := &Builder[T]{root: x.root.Load().cloneShallow()}
+
+	if err := fn(builder); err != nil {
+		return err
+	}
+
+	x.root.Store(builder.root)
+	return nil
 }
@@ -0,0 +1,70 @@
+package domain_suffix_trie
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSyncDomainSuffixTrieNode_AddDomainSuffix(t *testing.T) {
+	trie := NewSyncDomainSuffixTrie[string]()
+	err := trie.AddDomainSuffix("google.com", "谷歌")
+	assert.Nil(t, err)
+	assert.Equal(t, "谷歌", trie.FindMatchDomainSuffixPayload("api.google.com"))
+}
+
+func TestSyncDomainSuffixTrieNode_BatchUpdate(t *testing.T) {
+	trie := NewSyncDomainSuffixTrie[string]()
+
+	err := trie.BatchUpdate(func(b *Builder[string]) error {
+		if err := b.AddDomainSuffix("google.com", "谷歌"); err != nil {
+			return err
+		}
+		return b.AddDomainSuffix("baidu.com", "百度")
+	})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "谷歌", trie.FindMatchDomainSuffixPayload("api.google.com"))
+	assert.Equal(t, "百度", trie.FindMatchDomainSuffixPayload("api.baidu.com"))
+}
+
+func TestSyncDomainSuffixTrieNode_BatchUpdate_RollbackOnError(t *testing.T) {
+	trie := NewSyncDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌"))
+
+	err := trie.BatchUpdate(func(b *Builder[string]) error {
+		_ = b.AddDomainSuffix("baidu.com", "百度")
+		return DomainSuffixIsEmptyError
+	})
+	assert.NotNil(t, err)
+
+	// 回调返回error之后，这次更新不应该被发布出去
+	assert.Equal(t, "", trie.FindMatchDomainSuffixPayload("api.baidu.com"))
+	assert.Equal(t, "谷歌", trie.FindMatchDomainSuffixPayload("api.google.com"))
+}
+
+func TestSyncDomainSuffixTrieNode_ConcurrentReadDuringWrite(t *testing.T) {
+	trie := NewSyncDomainSuffixTrie[string]()
+	assert.Nil(t, trie.AddDomainSuffix("google.com", "谷歌"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// 读的过程中不应该panic，也不应该看到只插入了一半的中间状态
+			payload := trie.FindMatchDomainSuffixPayload("api.google.com")
+			assert.Equal(t, "谷歌", payload)
+		}(i)
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_ = trie.AddDomainSuffix("map.google.com", "谷歌地图")
+	}()
+
+	wg.Wait()
+	assert.Equal(t, "谷歌地图", trie.FindMatchDomainSuffixPayload("test.map.google.com"))
+}